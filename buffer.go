@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+import (
+	"io"
+)
+
+// maxConsecutiveEmptyReads is the number of reads returning no data and
+// no error after which a reader gives up, as bufio does.
+const maxConsecutiveEmptyReads = 100
+
+// reader is the subset of bufio.Reader chunkers read through, over a
+// buffer that may be provided by the caller rather than allocated.
+type reader struct {
+	buf  []byte
+	rd   io.Reader
+	r, w int
+	err  error
+}
+
+func newReader(rd io.Reader, buf []byte) *reader {
+	return &reader{buf: buf, rd: rd}
+}
+
+// Reset discards any buffered data and reads from rd, keeping the buffer.
+func (b *reader) Reset(rd io.Reader) {
+	*b = reader{buf: b.buf, rd: rd}
+}
+
+// Buffered returns the number of bytes that can be read from the buffer.
+func (b *reader) Buffered() int {
+	return b.w - b.r
+}
+
+// Discard skips the next n bytes, which must be buffered.
+func (b *reader) Discard(n int) {
+	b.r += n
+}
+
+// Peek returns the next n bytes without advancing the reader, or all of
+// those buffered along with the error that stopped the read if fewer. n
+// must not exceed the size of the buffer.
+func (b *reader) Peek(n int) ([]byte, error) {
+	for b.w-b.r < n && b.err == nil {
+		b.fill()
+	}
+	if avail := b.w - b.r; avail < n {
+		err := b.err
+		b.err = nil
+		return b.buf[b.r:b.w], err
+	}
+	return b.buf[b.r : b.r+n], nil
+}
+
+// fill slides the buffered data to the start of the buffer and reads a
+// new block into it.
+func (b *reader) fill() {
+	if b.r > 0 {
+		copy(b.buf, b.buf[b.r:b.w])
+		b.w -= b.r
+		b.r = 0
+	}
+	for i := maxConsecutiveEmptyReads; i > 0; i-- {
+		n, err := b.rd.Read(b.buf[b.w:])
+		if n < 0 {
+			panic("chunkers: reader returned negative count from Read")
+		}
+		b.w += n
+		if err != nil {
+			b.err = err
+			return
+		}
+		if n > 0 {
+			return
+		}
+	}
+	b.err = io.ErrNoProgress
+}
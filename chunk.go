@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"hash"
+	"io"
+)
+
+// Chunk is a chunk along with its place in the stream and its digest, as
+// returned by NextChunk.
+type Chunk struct {
+	// Offset counts from the start of the reader, or of the io.ReaderAt
+	// given to NewChunkerAt, like the offsets reported by Split.
+	Offset uint64
+	Length uint32
+	Data   []byte
+
+	// Digest is computed with ChunkerOpts.Hash, SHA-256 if nil.
+	Digest []byte
+}
+
+// NextChunk returns the next chunk like Next, along with its offset and
+// digest, so that callers need neither track offsets nor hash chunks
+// again. Chunks whose digest is among the ChunkerOpts.RecentDigests last
+// ones are flagged with FlagRepeat. With BorrowBuffers, the digest, like
+// the data, is only valid until the next call.
+func (chunker *Chunker) NextChunk() (Chunk, error) {
+	data, err := chunker.Next()
+	if len(data) == 0 {
+		return Chunk{}, err
+	}
+
+	if chunker.hasher == nil {
+		newHash := chunker.options.Hash
+		if newHash == nil {
+			newHash = sha256.New
+		}
+		chunker.hasher = newHash()
+		chunker.digest = make([]byte, 0, chunker.hasher.Size())
+		chunker.recent = newRecentDigests(chunker.options.RecentDigests, chunker.hasher.Size())
+	}
+	chunker.hasher.Reset()
+	chunker.hasher.Write(data)
+	digest := chunker.hasher.Sum(chunker.digest[:0])
+	if chunker.recent.seen(digest) {
+		chunker.flags |= FlagRepeat
+	}
+	if !chunker.options.BorrowBuffers {
+		digest = bytes.Clone(digest)
+	}
+
+	return Chunk{
+		Offset: uint64(chunker.position),
+		Length: uint32(len(data)),
+		Data:   data,
+		Digest: digest,
+	}, err
+}
+
+// NextBoundary returns the offset and length of the next chunk like
+// NextChunk, without copying or hashing it, for callers indexing a file
+// whose chunks they will later read by range or through a mapping.
+func (chunker *Chunker) NextBoundary() (uint64, uint32, error) {
+	data, err := chunker.next()
+	if len(data) == 0 {
+		return 0, 0, err
+	}
+	return uint64(chunker.position), uint32(len(data)), err
+}
+
+// SplitAll chunks data held in memory in one call, returning its chunks
+// in order. Their Data slices data rather than copying it, and they carry
+// no Digest unless computed by SplitAllDigest.
+func SplitAll(algorithm string, data []byte, opts *ChunkerOpts) ([]Chunk, error) {
+	return splitAll(algorithm, data, opts, false)
+}
+
+// SplitAllDigest behaves like SplitAll but also computes the digest of
+// every chunk, with ChunkerOpts.Hash.
+func SplitAllDigest(algorithm string, data []byte, opts *ChunkerOpts) ([]Chunk, error) {
+	return splitAll(algorithm, data, opts, true)
+}
+
+func splitAll(algorithm string, data []byte, opts *ChunkerOpts, digests bool) ([]Chunk, error) {
+	chunker, err := NewChunker(algorithm, bytes.NewReader(data), opts)
+	if err != nil {
+		return nil, err
+	}
+	var hasher hash.Hash
+	if digests {
+		newHash := chunker.options.Hash
+		if newHash == nil {
+			newHash = sha256.New
+		}
+		hasher = newHash()
+	}
+
+	chunks := make([]Chunk, 0, len(data)/max(chunker.normalSize, 1)+1)
+	for {
+		offset, length, err := chunker.NextBoundary()
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if length != 0 {
+			chunk := Chunk{Offset: offset, Length: length, Data: data[offset : offset+uint64(length)]}
+			if hasher != nil {
+				hasher.Reset()
+				hasher.Write(chunk.Data)
+				chunk.Digest = hasher.Sum(nil)
+			}
+			chunks = append(chunks, chunk)
+		}
+		if err == io.EOF {
+			return chunks, nil
+		}
+	}
+}
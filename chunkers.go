@@ -17,15 +17,125 @@ package chunkers
  */
 
 import (
-	"bufio"
+	"crypto/sha256"
 	"errors"
+	"fmt"
+	"hash"
 	"io"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 )
 
 type ChunkerOpts struct {
 	MinSize    int
 	MaxSize    int
 	NormalSize int
+
+	// MinTailSize vetoes a cut that would leave fewer than MinTailSize
+	// bytes before EOF, merging the tail into the preceding chunk.
+	// It only applies once the rest of the stream is buffered, so the
+	// merged chunk never exceeds MaxSize. Zero disables the veto.
+	MinTailSize int
+
+	// Polynomial is the irreducible polynomial used by Rabin fingerprint
+	// based algorithms, zero selects the algorithm's own default.
+	//
+	// Deprecated: set the Options of the algorithm in Extension instead,
+	// such as with restic.WithPolynomial. Algorithms still use Polynomial
+	// when their own is zero.
+	Polynomial uint64
+
+	// Window sizes the region examined around each candidate cutpoint by
+	// algorithms that let it be tuned, such as the radius of maxp. Zero
+	// selects the algorithm's own default.
+	//
+	// Deprecated: set the Options of the algorithm in Extension instead,
+	// such as with maxp.WithWindow. Algorithms still use Window when their
+	// own is zero.
+	Window int
+
+	// NormalizationLevel sets how far normalized chunking tightens the
+	// cut condition before NormalSize and relaxes it after, from 1 to 3,
+	// trading deduplication for a narrower chunk size distribution in
+	// algorithms that let it be tuned, such as fastcdc. -1 disables
+	// normalization and zero selects the algorithm's own default.
+	//
+	// Deprecated: set the Options of the algorithm in Extension instead,
+	// such as with fastcdc.WithNormalization. Algorithms still use
+	// NormalizationLevel when their own is zero.
+	NormalizationLevel int
+
+	// Hints are stream offsets, in increasing order, where format-aware
+	// analyzers would like chunks to start, such as the members of a
+	// container. A cutpoint within HintTolerance bytes of a hint moves to
+	// it, as long as the chunk keeps at least MinSize bytes and at most
+	// MaxSize. Offsets count from the start of the reader, or of the
+	// io.ReaderAt given to NewChunkerAt. See ReadHints.
+	Hints         []int64
+	HintTolerance int
+
+	// Scanner returns a BoundaryScanner proposing hints as the stream is
+	// read, for formats whose structure is only known from their content,
+	// such as the members of a tar archive. Proposed cutpoints are snapped
+	// to like Hints. Nil disables it.
+	Scanner func() BoundaryScanner
+
+	// BorrowBuffers hands out chunks that alias the chunker's internal
+	// buffer: they are only valid until the next call to Next or until
+	// the Split callback returns, and must not be modified. This avoids
+	// a copy per chunk, steady-state chunking then makes no allocation.
+	// When false, every chunk is an owned copy that the caller may
+	// retain, at the cost of one allocation per chunk. Copy always
+	// borrows, as io.Writer implementations must not retain the slices
+	// they are handed.
+	BorrowBuffers bool
+
+	// Hash builds the hash identifying chunks in SplitDigest and
+	// SplitDedup, nil selects SHA-256. NewXXH64 or hash/fnv trade
+	// collision resistance for speed where IDs stay local.
+	Hash func() hash.Hash
+
+	// MaxLatency bounds how long a chunk waits for more data on a live
+	// stream, such as an io.Pipe: once MaxLatency elapsed since its first
+	// byte was received without a cutpoint being found, what is buffered
+	// is emitted as a chunk flagged with FlagTimeCut. Time cuts depend on
+	// when data arrives, so they break the reproducibility of cutpoints
+	// until the next content-defined one. Zero disables it.
+	MaxLatency time.Duration
+
+	// RecentDigests is the number of most recent chunk digests that
+	// SplitDigest and SplitDedup remember to flag chunks repeating within
+	// the stream, such as the identical blocks of VM images, without a
+	// lookup in the caller's index. Zero disables it.
+	RecentDigests int
+
+	// Tagger is called with every chunk as it is emitted, along with its
+	// stream offset, and returns the tags to attach to it, such as a PII
+	// flag or a storage tier, read back with Chunker.Tags while the chunk
+	// is the last returned. Classifying chunks as they are cut spares a
+	// second pass over the data. The chunk is only valid until Tagger
+	// returns. Tags do not affect cutpoints. Nil disables tagging.
+	Tagger func(offset, length uint64, chunk []byte) map[string]string
+
+	// Extension carries the options specific to an algorithm, such as
+	// *ultracdc.Options, and is ignored by the others. Algorithms provide
+	// Options setting it, such as ultracdc.WithStride.
+	Extension any
+
+	// Key is a secret from which algorithms derive their constants, such
+	// as their Gear table, so that cutpoints cannot be predicted by anyone
+	// without it and chunk sizes do not reveal known content. Algorithms
+	// with no constants to derive refuse it with ErrUnkeyed. Nil disables
+	// keying.
+	Key []byte
+
+	// StreamIdentity enables the computation of the stream identity,
+	// see Chunker.StreamIdentity. It cannot be combined with MaxLatency.
+	StreamIdentity bool
 }
 
 type ChunkerImplementation interface {
@@ -34,12 +144,61 @@ type ChunkerImplementation interface {
 	Algorithm(*ChunkerOpts, []byte, int) int
 }
 
+// StatefulImplementation is implemented by algorithms whose cutpoints
+// depend on the chunks already produced. Each chunker allocates its own
+// implementation, so state lives for the duration of one stream: Emit is
+// called with every chunk, in order, once its cutpoint is final.
+type StatefulImplementation interface {
+	ChunkerImplementation
+	Emit(*ChunkerOpts, []byte)
+}
+
+// ResolvingImplementation is implemented by algorithms that select their
+// own value when an option such as Window or NormalizationLevel is zero.
+// ResolveOptions sets those options to the values in effect.
+type ResolvingImplementation interface {
+	ChunkerImplementation
+	ResolveOptions(*ChunkerOpts)
+}
+
 type Chunker struct {
-	rd             *bufio.Reader
+	name           string
+	allocator      func() ChunkerImplementation
+	rd             *reader
+	latency        *latencyReader
 	options        *ChunkerOpts
 	implementation ChunkerImplementation
+	stateful       StatefulImplementation
+	flagging       FlaggingImplementation
+	leveling       LevelingImplementation
 
 	cutpoint int
+	identity hash.Hash
+
+	flags ChunkFlags
+	level int
+	tags  map[string]string
+	cuts  CutStats
+
+	// hashing state of NextChunk, set up by its first call
+	hasher hash.Hash
+	digest []byte
+	recent *recentDigests
+
+	// offset of the current chunk and index of the first hint past it
+	position int64
+	hint     int
+
+	// chunk returned by Peek, returned again by the next call to next
+	peeked  bool
+	peek    []byte
+	peekErr error
+
+	// hints proposed by the scanner past the current chunk, and offset of
+	// the first byte it was not handed yet
+	scanner  BoundaryScanner
+	proposed []int64
+	scanned  int64
 
 	maxSize    int
 	minSize    int
@@ -58,50 +217,491 @@ func (c *Chunker) NormalSize() int {
 	return c.options.NormalSize
 }
 
+// AlgorithmName returns the versioned name of the algorithm of the
+// chunker, see Resolve.
+func (c *Chunker) AlgorithmName() string {
+	return c.name
+}
+
+// Options returns a copy of the options the chunker runs with, those in
+// effect for the algorithm when none were passed, for tools recording
+// which parameters produced a chunking. Fields the algorithm defaults
+// when zero, such as Window, hold the values in effect. Slices are
+// shared, Key included, which must not be recorded alongside chunks.
+func (c *Chunker) Options() ChunkerOpts {
+	opts := *c.options
+	if resolving, ok := c.implementation.(ResolvingImplementation); ok {
+		resolving.ResolveOptions(&opts)
+	}
+	return opts
+}
+
+// MemoryFootprint returns the number of bytes of buffer memory held by
+// the chunker, which does not change over its lifetime.
+func (c *Chunker) MemoryFootprint() int {
+	return len(c.rd.buf)
+}
+
+// Close stops the goroutine reading ahead of the chunker when MaxLatency
+// is set, making a pending or later Next fail with ErrClosed. Callers
+// abandoning a live stream must close the chunker, it is a no-op
+// otherwise.
+func (c *Chunker) Close() error {
+	if c.latency == nil {
+		return nil
+	}
+	return c.latency.Close()
+}
+
+// minBufferSize is the smallest buffer a chunker reads through.
+const minBufferSize = 64 * 1024
+
+// bufferSize returns the size of the buffer a chunker reads through, room
+// for two chunks of maxSize so that the buffer is only refilled every few
+// chunks, and small chunks do not cost a read of the underlying reader
+// each. A single one fits where two would overflow an int, as a MaxSize
+// of 1GB does on 32-bit platforms.
+func bufferSize(maxSize int) int {
+	if maxSize > math.MaxInt/2 {
+		return maxSize
+	}
+	return max(2*maxSize, minBufferSize)
+}
+
+// ErrUnknownAlgorithm is returned for an algorithm name that is not
+// registered.
+var ErrUnknownAlgorithm = errors.New("unknown algorithm")
+
+// ErrInvalidOptions wraps the errors of options refused for an algorithm,
+// which errors.Is also matches against the algorithm's own, such as
+// fastcdc.ErrMinSize.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// ErrChunkTooLarge is returned by Next when an algorithm cuts past the
+// MaxSize bytes it was handed, which is a bug of the algorithm.
+var ErrChunkTooLarge = errors.New("chunk larger than MaxSize")
+
+// chunkers holds the algorithms by versioned name, such as "fastcdc@v1",
+// and versions the versioned name each bare name resolves to, both
+// guarded by registry so that plugins can register algorithms at any time.
 var chunkers map[string]func() ChunkerImplementation = make(map[string]func() ChunkerImplementation)
+var versions = make(map[string]string)
+var registry sync.RWMutex
 
+// Register registers an algorithm under a versioned name, such as
+// "fastcdc@v2", so that backup formats can pin the cutpoints of every
+// version forever while the library improves its algorithms. A bare name
+// registers version v1. The bare name resolves to the first version
+// registered, see SetDefaultVersion and Resolve. Registering a name twice
+// fails rather than replacing the algorithm.
 func Register(name string, implementation func() ChunkerImplementation) error {
+	base, name, err := versionedName(name)
+	if err != nil {
+		return err
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
 	if _, exists := chunkers[name]; exists {
 		return errors.New("algorithm already registered")
 	}
 	chunkers[name] = implementation
+	if _, exists := versions[base]; !exists {
+		versions[base] = name
+	}
+	return nil
+}
+
+// MustRegister is like Register but panics if the algorithm cannot be
+// registered, for init functions.
+func MustRegister(name string, implementation func() ChunkerImplementation) {
+	if err := Register(name, implementation); err != nil {
+		panic("chunkers: " + name + ": " + err.Error())
+	}
+}
+
+// Unregister removes an algorithm registered under name, a bare name
+// meaning v1 as for Register, along with the options set for it by
+// SetDefaultOptions. Chunkers already created keep running it. A bare
+// name resolving to the version removed resolves to the first of those
+// left, sorted.
+func Unregister(name string) error {
+	base, name, err := versionedName(name)
+	if err != nil {
+		return err
+	}
+
+	registry.Lock()
+	if _, exists := chunkers[name]; !exists {
+		registry.Unlock()
+		return ErrUnknownAlgorithm
+	}
+	delete(chunkers, name)
+	if versions[base] == name {
+		delete(versions, base)
+		for _, other := range slices.Sorted(maps.Keys(chunkers)) {
+			if strings.HasPrefix(other, base+"@") {
+				versions[base] = other
+				break
+			}
+		}
+	}
+	registry.Unlock()
+
+	defaults.Lock()
+	delete(defaults.options, name)
+	defaults.Unlock()
+	return nil
+}
+
+// versionedName returns the bare and versioned names of an algorithm
+// name, v1 if it has no version.
+func versionedName(name string) (string, string, error) {
+	base, version, versioned := strings.Cut(name, "@")
+	if !versioned {
+		version = "v1"
+	}
+	if base == "" || version == "" || strings.Contains(version, "@") {
+		return "", "", errors.New("invalid algorithm name")
+	}
+	return base, base + "@" + version, nil
+}
+
+// SetDefaultVersion sets the version of an algorithm its bare name
+// resolves to process-wide.
+func SetDefaultVersion(name, version string) error {
+	versioned := name + "@" + version
+	registry.Lock()
+	defer registry.Unlock()
+	if _, exists := chunkers[versioned]; !exists {
+		return ErrUnknownAlgorithm
+	}
+	versions[name] = versioned
+	return nil
+}
+
+// Resolve returns the versioned name an algorithm name refers to, the
+// name itself if already versioned, to be recorded where cutpoints must
+// be reproduced by later releases.
+func Resolve(name string) (string, error) {
+	name, _, exists := lookup(name)
+	if !exists {
+		return "", ErrUnknownAlgorithm
+	}
+	return name, nil
+}
+
+// lookup returns the versioned name an algorithm name resolves to and the
+// allocator of its implementation.
+func lookup(name string) (string, func() ChunkerImplementation, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	if versioned, exists := versions[name]; exists {
+		name = versioned
+	}
+	implementationAllocator, exists := chunkers[name]
+	return name, implementationAllocator, exists
+}
+
+// List returns the versioned names of the registered algorithms, sorted.
+func List() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	return slices.Sorted(maps.Keys(chunkers))
+}
+
+// Exists reports whether an algorithm is registered under name, for
+// configuration loaders to check the names they are given.
+func Exists(name string) bool {
+	_, _, exists := lookup(name)
+	return exists
+}
+
+// DefaultOptionsFor returns a copy of the options an algorithm runs with
+// when none are passed, its own or those set by SetDefaultOptions.
+func DefaultOptionsFor(name string) (*ChunkerOpts, error) {
+	name, implementationAllocator, exists := lookup(name)
+	if !exists {
+		return nil, ErrUnknownAlgorithm
+	}
+	return defaultOptions(name, implementationAllocator), nil
+}
+
+// defaults holds the options set by SetDefaultOptions
+var defaults struct {
+	sync.RWMutex
+	options map[string]ChunkerOpts
+}
+
+// SetDefaultOptions overrides the options of an algorithm used process-wide
+// wherever none are passed, such as NewChunker with nil options, so that
+// applications shipping tuned profiles need not thread them through every
+// call site. The options are validated and copied, slices they refer to
+// are not and must not be modified. Nil options restore the algorithm's
+// own defaults.
+func SetDefaultOptions(algorithm string, opts *ChunkerOpts) error {
+	algorithm, implementationAllocator, exists := lookup(algorithm)
+	if !exists {
+		return ErrUnknownAlgorithm
+	}
+	if opts != nil {
+		if err := validate(implementationAllocator(), opts); err != nil {
+			return err
+		}
+	}
+
+	defaults.Lock()
+	defer defaults.Unlock()
+	if opts == nil {
+		delete(defaults.options, algorithm)
+		return nil
+	}
+	if defaults.options == nil {
+		defaults.options = make(map[string]ChunkerOpts)
+	}
+	defaults.options[algorithm] = *opts
+	return nil
+}
+
+// validate checks opts for an implementation, wrapping the errors of the
+// algorithm in ErrInvalidOptions.
+func validate(implementation ChunkerImplementation, opts *ChunkerOpts) error {
+	if err := implementation.Validate(opts); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidOptions, err)
+	}
+	if _, keyed := implementation.(KeyedImplementation); len(opts.Key) != 0 && !keyed {
+		return ErrUnkeyed
+	}
 	return nil
 }
 
+// defaultOptions returns a copy of the options in effect for an algorithm
+// when none are passed.
+func defaultOptions(algorithm string, implementationAllocator func() ChunkerImplementation) *ChunkerOpts {
+	defaults.RLock()
+	opts, exists := defaults.options[algorithm]
+	defaults.RUnlock()
+	if !exists {
+		return implementationAllocator().DefaultOptions()
+	}
+	return &opts
+}
+
+// NewChunker returns a chunker cutting reader with an algorithm, nil
+// options selecting those in effect for it. Options the algorithm refuses
+// fail with an error matching ErrInvalidOptions.
 func NewChunker(algorithm string, reader io.Reader, opts *ChunkerOpts) (*Chunker, error) {
+	return newChunker(algorithm, reader, opts, nil)
+}
+
+var ErrBufferSize = errors.New("buffer smaller than RequiredBufferSize")
+
+// RequiredBufferSize returns the size of the buffer a chunker reads
+// through with opts, which must not be nil, the least NewChunkerWithBuffer
+// accepts.
+func RequiredBufferSize(opts *ChunkerOpts) int {
+	return bufferSize(opts.MaxSize)
+}
+
+// NewChunkerWithBuffer behaves like NewChunker, but reads through buf
+// rather than a buffer of its own, so that applications can pool buffers
+// or allocate them from arenas. buf must hold at least
+// RequiredBufferSize bytes, a larger one is used whole. It belongs to the
+// chunker until the chunker is no longer used, and chunks borrow from it
+// with BorrowBuffers. With MaxLatency set, the reader running in the
+// background still allocates its own.
+func NewChunkerWithBuffer(algorithm string, reader io.Reader, opts *ChunkerOpts, buf []byte) (*Chunker, error) {
+	if buf == nil {
+		return nil, ErrBufferSize
+	}
+	return newChunker(algorithm, reader, opts, buf)
+}
+
+// newChunker returns a chunker reading through buf, or through a buffer
+// of its own if nil.
+func newChunker(algorithm string, reader io.Reader, opts *ChunkerOpts, buf []byte) (*Chunker, error) {
 	var implementationAllocator func() ChunkerImplementation
 
-	implementationAllocator, exists := chunkers[algorithm]
+	algorithm, implementationAllocator, exists := lookup(algorithm)
 	if !exists {
-		return nil, errors.New("unknown algorithm")
+		return nil, ErrUnknownAlgorithm
 	}
 
 	if opts == nil {
-		opts = implementationAllocator().DefaultOptions()
+		opts = defaultOptions(algorithm, implementationAllocator)
+	}
+
+	if opts.StreamIdentity && opts.MaxLatency > 0 {
+		return nil, ErrTimeCutIdentity
 	}
 
 	chunker := &Chunker{}
+	chunker.name = algorithm
+	chunker.allocator = implementationAllocator
 	chunker.implementation = implementationAllocator()
+	chunker.stateful, _ = chunker.implementation.(StatefulImplementation)
+	chunker.flagging, _ = chunker.implementation.(FlaggingImplementation)
+	chunker.leveling, _ = chunker.implementation.(LevelingImplementation)
+	if err := validate(chunker.implementation, opts); err != nil {
+		return nil, err
+	}
 	chunker.options = opts
-	chunker.rd = bufio.NewReaderSize(reader, int(chunker.options.MaxSize)*2)
+	if buf == nil {
+		buf = make([]byte, bufferSize(opts.MaxSize))
+	} else if len(buf) < RequiredBufferSize(opts) {
+		return nil, ErrBufferSize
+	}
+	if opts.Scanner != nil {
+		chunker.scanner = opts.Scanner()
+	}
+	if opts.MaxLatency > 0 {
+		chunker.latency = newLatencyReader(reader, opts.MaxLatency, opts.MaxSize)
+		reader = chunker.latency
+	}
+	chunker.rd = newReader(reader, buf)
 
 	chunker.minSize = chunker.options.MinSize
 	chunker.maxSize = chunker.options.MaxSize
 	chunker.normalSize = chunker.options.NormalSize
 
+	if opts.StreamIdentity {
+		chunker.identity = newIdentity(algorithm, opts)
+	}
+
+	return chunker, nil
+}
+
+var ErrStatefulAnchor = errors.New("algorithm depends on previous chunks and cannot resume at an anchor")
+
+// NewChunkerAt resumes chunking r at anchor, which must be a boundary of a
+// previous run with the same algorithm and options, or zero. Chunks only
+// depend on the bytes from their start, so the boundaries produced match
+// those of the previous run from the anchor onwards, and Split reports
+// offsets relative to the start of r. Algorithms that depend on previous
+// chunks cannot give that guarantee and are refused.
+func NewChunkerAt(algorithm string, r io.ReaderAt, anchor int64, opts *ChunkerOpts) (*Chunker, error) {
+	if anchor < 0 {
+		return nil, errors.New("negative anchor")
+	}
+	chunker, err := NewChunker(algorithm, io.NewSectionReader(r, anchor, math.MaxInt64-anchor), opts)
+	if err != nil {
+		return nil, err
+	}
+	if chunker.stateful != nil {
+		return nil, ErrStatefulAnchor
+	}
+	chunker.position = anchor
+	chunker.scanned = anchor
 	return chunker, nil
 }
 
+// Reset rebinds the chunker to r, as a new stream chunked with the same
+// algorithm and options, like bufio.Reader.Reset: its buffer is kept,
+// so that chunking many small files costs no allocation per file beyond
+// the chunks handed out. Offsets restart at zero, even for a chunker
+// created by NewChunkerAt. With MaxLatency set, the background reader of
+// the previous stream is stopped and a new one started.
+func (chunker *Chunker) Reset(r io.Reader) {
+	if chunker.latency != nil {
+		chunker.latency.Close()
+		chunker.latency = newLatencyReader(r, chunker.options.MaxLatency, chunker.options.MaxSize)
+		r = chunker.latency
+	}
+	chunker.rd.Reset(r)
+
+	if chunker.stateful != nil {
+		// the state of the algorithm belongs to the previous stream
+		chunker.implementation = chunker.allocator()
+		chunker.stateful, _ = chunker.implementation.(StatefulImplementation)
+		chunker.flagging, _ = chunker.implementation.(FlaggingImplementation)
+		chunker.leveling, _ = chunker.implementation.(LevelingImplementation)
+	}
+	if chunker.identity != nil {
+		chunker.identity = newIdentity(chunker.name, chunker.options)
+	}
+	if chunker.scanner != nil {
+		chunker.scanner = chunker.options.Scanner()
+	}
+	if chunker.recent != nil {
+		chunker.recent.reset()
+	}
+
+	chunker.cutpoint = 0
+	chunker.flags = 0
+	chunker.level = 0
+	chunker.tags = nil
+	chunker.cuts = CutStats{}
+	chunker.position = 0
+	chunker.hint = 0
+	chunker.proposed = chunker.proposed[:0]
+	chunker.scanned = 0
+	chunker.peeked = false
+	chunker.peek = nil
+	chunker.peekErr = nil
+}
+
+// Next returns the next chunk, see ChunkerOpts.BorrowBuffers for how long
+// it remains valid.
 func (chunker *Chunker) Next() ([]byte, error) {
+	chunk, err := chunker.next()
+	if !chunker.options.BorrowBuffers && len(chunk) != 0 {
+		chunk = append([]byte(nil), chunk...)
+	}
+	return chunk, err
+}
+
+// Peek returns the next chunk without consuming it: the next call to
+// Next, NextChunk or Skip returns it again, and Flags, Tags and Level
+// already report it. Like chunks returned by Next, it is a copy unless
+// BorrowBuffers is set.
+func (chunker *Chunker) Peek() ([]byte, error) {
+	if !chunker.peeked {
+		chunker.peek, chunker.peekErr = chunker.next()
+		chunker.peeked = true
+	}
+	chunk := chunker.peek
+	if !chunker.options.BorrowBuffers && len(chunk) != 0 {
+		chunk = append([]byte(nil), chunk...)
+	}
+	return chunk, chunker.peekErr
+}
+
+// Skip discards the next n chunks without copying them out, such as those
+// of a file prefix known to be unchanged, and returns how many it
+// skipped, fewer than n along with io.EOF once the stream is exhausted.
+func (chunker *Chunker) Skip(n int) (int, error) {
+	skipped := 0
+	for skipped < n {
+		chunk, err := chunker.next()
+		if len(chunk) != 0 {
+			skipped++
+		}
+		if err != nil {
+			return skipped, err
+		}
+	}
+	return skipped, nil
+}
+
+// next returns the next chunk borrowed from the internal buffer.
+func (chunker *Chunker) next() ([]byte, error) {
+	if chunker.peeked {
+		chunker.peeked = false
+		return chunker.peek, chunker.peekErr
+	}
 	if chunker.cutpoint != 0 {
 		// Discard is guaranteed to succeed here, do not check for error
 		chunker.rd.Discard(chunker.cutpoint)
+		chunker.position += int64(chunker.cutpoint)
 		chunker.cutpoint = 0
+		if chunker.latency != nil {
+			chunker.latency.restart(chunker.rd.Buffered() != 0)
+		}
 	}
 
 	data, err := chunker.rd.Peek(chunker.maxSize)
-	if err != nil && err != io.EOF {
-		return nil, err
+	if err != nil && err != io.EOF && err != errLatency {
+		return nil, fmt.Errorf("read at offset %d: %w", chunker.position+int64(len(data)), err)
 	}
 
 	n := len(data)
@@ -109,26 +709,86 @@ func (chunker *Chunker) Next() ([]byte, error) {
 		return nil, io.EOF
 	}
 
+	if chunker.scanner != nil {
+		seen := int(chunker.scanned - chunker.position)
+		chunker.proposed = chunker.scanner.Scan(chunker.proposed, chunker.scanned, data[seen:])
+		chunker.scanned = chunker.position + int64(n)
+	}
+
 	cutpoint := chunker.implementation.Algorithm(chunker.options, data, n)
+	if cutpoint > n {
+		return nil, ErrChunkTooLarge
+	}
+	var flags ChunkFlags
+	if len(chunker.options.Hints) != 0 || chunker.scanner != nil {
+		var hinted bool
+		if cutpoint, hinted = chunker.snap(cutpoint, n); hinted {
+			flags = FlagHint
+		}
+	}
+	var merged bool
+	if err == io.EOF && cutpoint != n && n-cutpoint < chunker.options.MinTailSize {
+		// Peek hit EOF, so data holds the rest of the stream
+		cutpoint, flags, merged = n, 0, true
+	}
+	if err == errLatency && cutpoint == n {
+		flags = FlagTimeCut
+	}
 	chunker.cutpoint = cutpoint
+	chunker.flag(data, cutpoint, flags, merged)
+	chunker.level = 0
+	if chunker.leveling != nil && flags == 0 && !merged {
+		chunker.level = chunker.leveling.Level()
+	}
 
-	if cutpoint < chunker.minSize {
+	if chunker.stateful != nil {
+		chunker.stateful.Emit(chunker.options, data[:cutpoint])
+	}
+	if chunker.identity != nil {
+		digest := sha256.Sum256(data[:cutpoint])
+		chunker.identity.Write(digest[:])
+	}
+	if chunker.options.Tagger != nil {
+		chunker.tags = chunker.options.Tagger(uint64(chunker.position), uint64(cutpoint), data[:cutpoint])
+	}
+
+	if cutpoint < chunker.minSize && err != errLatency {
 		return data[:cutpoint], io.EOF
 	}
 
 	return data[:cutpoint], nil
 }
 
+// OffsetWriter is implemented by writers that need to know where the
+// chunks they are handed start in the stream, such as one storing them
+// at their offset. Copy and CopyQueue call WriteChunk instead of Write
+// when dst implements it. Offsets count like those reported by Split.
+type OffsetWriter interface {
+	WriteChunk(offset uint64, p []byte) (int, error)
+}
+
+// write writes a chunk at offset to dst, through WriteChunk if dst is an
+// OffsetWriter.
+func write(dst io.Writer, offset uint64, chunk []byte) (int, error) {
+	if ow, ok := dst.(OffsetWriter); ok {
+		return ow.WriteChunk(offset, chunk)
+	}
+	return dst.Write(chunk)
+}
+
+// Copy writes the stream to dst one chunk per Write, in stream order, and
+// returns io.EOF once it is exhausted. See CopyQueue to overlap chunking
+// with writes, and OffsetWriter for writers that need chunk offsets.
 func (chunker *Chunker) Copy(dst io.Writer) (int64, error) {
 	nbytes := int64(0)
 	for {
-		chunk, err := chunker.Next()
+		chunk, err := chunker.next()
 		if err != nil && err != io.EOF {
 			return nbytes, err
 		}
 
 		if len(chunk) != 0 {
-			if _, werr := dst.Write(chunk); werr != nil {
+			if _, werr := write(dst, uint64(chunker.position), chunk); werr != nil {
 				return nbytes, werr
 			}
 		}
@@ -141,8 +801,7 @@ func (chunker *Chunker) Copy(dst io.Writer) (int64, error) {
 	return nbytes, io.EOF
 }
 
-func (chunker *Chunker) Split(callback func(offset, length uint, chunk []byte) error) error {
-	offset := uint(0)
+func (chunker *Chunker) Split(callback func(offset, length uint64, chunk []byte) error) error {
 	for {
 		chunk, err := chunker.Next()
 		if err != nil && err != io.EOF {
@@ -150,7 +809,7 @@ func (chunker *Chunker) Split(callback func(offset, length uint, chunk []byte) e
 		}
 
 		if len(chunk) != 0 {
-			if err = callback(offset, uint(len(chunk)), chunk); err != nil {
+			if err = callback(uint64(chunker.position), uint64(len(chunk)), chunk); err != nil {
 				return err
 			}
 		}
@@ -158,7 +817,6 @@ func (chunker *Chunker) Split(callback func(offset, length uint, chunk []byte) e
 		if err == io.EOF {
 			break
 		}
-		offset += uint(len(chunk))
 	}
 	return nil
 }
@@ -0,0 +1,195 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package bimodal
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+var ErrRatio = errors.New("ratio must be a power of two >= 2")
+var ErrSmallOptions = errors.New("small chunk sizes must be valid for the algorithm with 0 < NormalSize < the NormalSize of large chunks")
+
+// DefaultRatio is the ratio between large and small chunk sizes used when
+// none is given.
+const DefaultRatio = 8
+
+// Chunker implements bimodal chunking over any registered algorithm: the
+// stream is cut into large chunks, and only the new large chunks that
+// border a duplicate one are cut again into small chunks. Changes tend to
+// be localized, so the regions around the transitions between known and
+// new data are where small chunks recover most of the duplicates, while
+// the bulk of the stream keeps the lower per-chunk overhead of large
+// chunks.
+//
+// Stateful algorithms start afresh for every large chunk cut again, and
+// ChunkerOpts.Tagger is ignored, the callback seeing every chunk anyway.
+type Chunker struct {
+	algorithm string
+	large     *chunkers.Chunker
+	small     chunkers.ChunkerOpts
+	hasher    hash.Hash
+	digest    []byte
+	has       func(digest []byte) bool
+}
+
+// NewChunker returns a bimodal chunker cutting large chunks with opts, or
+// the algorithm's defaults if nil, and small chunks with every size
+// divided by ratio, or DefaultRatio if zero. For every large chunk, has is
+// called once and in order with its digest, computed with opts.Hash, and
+// reports whether it was seen before. The digest is only valid until has
+// returns. Sizes the algorithm refuses once divided by ratio fail with
+// ErrSmallOptions, along with the error of the algorithm.
+func NewChunker(algorithm string, reader io.Reader, opts *chunkers.ChunkerOpts, ratio int, has func(digest []byte) bool) (*Chunker, error) {
+	if ratio == 0 {
+		ratio = DefaultRatio
+	}
+	if ratio < 2 || ratio&(ratio-1) != 0 {
+		return nil, ErrRatio
+	}
+
+	var large chunkers.ChunkerOpts
+	if opts != nil {
+		large = *opts
+	}
+	// large chunks are held while looking at the next one
+	large.BorrowBuffers = false
+	large.StreamIdentity = false
+	// chunks reach the callback with no chunker to read their tags from
+	large.Tagger = nil
+
+	c := &Chunker{algorithm: algorithm, has: has}
+	var err error
+	if opts == nil {
+		c.large, err = chunkers.NewChunker(algorithm, reader, nil)
+	} else {
+		c.large, err = chunkers.NewChunker(algorithm, reader, &large)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opts != nil {
+		c.small = *opts
+	}
+	// small chunkers run over a large chunk held in memory: hints are
+	// stream offsets already honoured by large cutpoints, there is no
+	// live stream to flush and repeats are only tracked on large chunks
+	c.small.StreamIdentity = false
+	c.small.Hints = nil
+	c.small.Scanner = nil
+	c.small.Tagger = nil
+	c.small.MaxLatency = 0
+	c.small.RecentDigests = 0
+	c.small.MinSize = c.large.MinSize() / ratio
+	c.small.NormalSize = c.large.NormalSize() / ratio
+	c.small.MaxSize = c.large.MaxSize() / ratio
+	if err := c.validateSmall(); err != nil {
+		c.large.Close()
+		return nil, err
+	}
+
+	newHash := c.small.Hash
+	if newHash == nil {
+		newHash = sha256.New
+	}
+	c.hasher = newHash()
+	c.digest = make([]byte, 0, c.hasher.Size())
+	return c, nil
+}
+
+// validateSmall checks that small chunks are smaller than large ones, and
+// that the algorithm accepts their sizes.
+func (c *Chunker) validateSmall() error {
+	if c.small.NormalSize < 1 || c.small.NormalSize >= c.large.NormalSize() {
+		return ErrSmallOptions
+	}
+	small := c.small
+	if _, err := chunkers.NewOptions(c.algorithm, func(opts *chunkers.ChunkerOpts) { *opts = small }); err != nil {
+		return fmt.Errorf("%w: %w", ErrSmallOptions, err)
+	}
+	return nil
+}
+
+// Close behaves like chunkers.Chunker.Close.
+func (c *Chunker) Close() error {
+	return c.large.Close()
+}
+
+// nextLarge returns the next large chunk and whether it is a duplicate,
+// or a nil chunk once the stream is exhausted.
+func (c *Chunker) nextLarge() ([]byte, bool, error) {
+	chunk, err := c.large.Next()
+	if err != nil && err != io.EOF {
+		return nil, false, err
+	}
+	if len(chunk) == 0 {
+		return nil, false, nil
+	}
+	c.hasher.Reset()
+	c.hasher.Write(chunk)
+	return chunk, c.has(c.hasher.Sum(c.digest[:0])), nil
+}
+
+// Split behaves like chunkers.Chunker.Split, see ChunkerOpts.BorrowBuffers
+// for how long chunks remain valid.
+func (c *Chunker) Split(callback func(offset, length uint64, chunk []byte) error) error {
+	current, duplicate, err := c.nextLarge()
+	if err != nil {
+		return err
+	}
+
+	var offset uint64
+	var previous bool
+	for current != nil {
+		next, nextDuplicate, err := c.nextLarge()
+		if err != nil {
+			return err
+		}
+
+		if !duplicate && (previous || (next != nil && nextDuplicate)) {
+			err = c.split(offset, current, callback)
+		} else {
+			err = callback(offset, uint64(len(current)), current)
+		}
+		if err != nil {
+			return err
+		}
+
+		offset += uint64(len(current))
+		previous = duplicate
+		current, duplicate = next, nextDuplicate
+	}
+	return nil
+}
+
+// split cuts a large chunk into small chunks.
+func (c *Chunker) split(offset uint64, chunk []byte, callback func(offset, length uint64, chunk []byte) error) error {
+	small, err := chunkers.NewChunker(c.algorithm, bytes.NewReader(chunk), &c.small)
+	if err != nil {
+		return err
+	}
+	return small.Split(func(smallOffset, length uint64, chunk []byte) error {
+		return callback(offset+smallOffset, length, chunk)
+	})
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package bimodal
+
+import (
+	"bytes"
+	"errors"
+	mathrand2 "math/rand/v2"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+)
+
+type chunk struct {
+	offset, length uint64
+}
+
+func split(t *testing.T, data []byte, seen map[string]struct{}) []chunk {
+	has := func(digest []byte) bool {
+		_, exists := seen[string(digest)]
+		seen[string(digest)] = struct{}{}
+		return exists
+	}
+	chunker, err := NewChunker("fastcdc", bytes.NewReader(data), nil, 0, has)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	var chunks []chunk
+	var reassembled []byte
+	err = chunker.Split(func(offset, length uint64, data []byte) error {
+		if offset != uint64(len(reassembled)) {
+			t.Fatalf(`chunk at offset %d, expected %d`, offset, len(reassembled))
+		}
+		chunks = append(chunks, chunk{offset, length})
+		reassembled = append(reassembled, data...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if !bytes.Equal(data, reassembled) {
+		t.Fatalf(`chunks do not reassemble the input`)
+	}
+	return chunks
+}
+
+func Test_Bimodal(t *testing.T) {
+	var seed [32]byte
+	original := make([]byte, 8<<20)
+	mathrand2.NewChaCha8(seed).Read(original)
+	mutated := append([]byte{}, original...)
+	copy(mutated[4<<20:], "an edit in the middle of the stream")
+
+	seen := make(map[string]struct{})
+	largeChunks := func(data []byte) map[chunk]struct{} {
+		chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), nil)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		chunks := make(map[chunk]struct{})
+		chunker.Split(func(offset, length uint64, data []byte) error {
+			chunks[chunk{offset, length}] = struct{}{}
+			return nil
+		})
+		return chunks
+	}
+
+	// nothing is known yet, every chunk stays large
+	expected := largeChunks(original)
+	chunks := split(t, original, seen)
+	if len(chunks) != len(expected) {
+		t.Fatalf(`expected %d large chunks, got %d`, len(expected), len(chunks))
+	}
+	for _, c := range chunks {
+		if _, exists := expected[c]; !exists {
+			t.Fatalf(`chunk at offset %d was cut small`, c.offset)
+		}
+	}
+
+	// only the neighbourhood of the edit is cut small
+	const distance = 3 * 64 << 10
+	expected = largeChunks(mutated)
+	small := 0
+	for _, c := range split(t, mutated, seen) {
+		if _, exists := expected[c]; exists {
+			continue
+		}
+		if c.offset+c.length < 4<<20-distance || c.offset > 4<<20+distance {
+			t.Fatalf(`small chunk at offset %d is far from the edit`, c.offset)
+		}
+		small++
+	}
+	if small == 0 {
+		t.Fatalf(`no small chunks around the edit`)
+	}
+}
+
+func Test_Ratio(t *testing.T) {
+	has := func(digest []byte) bool { return false }
+	for _, ratio := range []int{-1, 1, 3, 12} {
+		if _, err := NewChunker("fastcdc", bytes.NewReader(nil), nil, ratio, has); err != ErrRatio {
+			t.Fatalf(`ratio %d: expected ErrRatio, got %v`, ratio, err)
+		}
+	}
+}
+
+func Test_SmallOptions(t *testing.T) {
+	has := func(digest []byte) bool { return false }
+	for _, test := range []struct {
+		opts     *chunkers.ChunkerOpts
+		ratio    int
+		expected error
+	}{
+		{nil, 0, nil},
+		{&chunkers.ChunkerOpts{MinSize: 128, NormalSize: 1 << 10, MaxSize: 8 << 10}, 2, nil},
+		// small MinSize below the 64B fastcdc accepts
+		{nil, 64, fastcdc.ErrMinSize},
+		{&chunkers.ChunkerOpts{MinSize: 64, NormalSize: 8 << 10, MaxSize: 64 << 10}, 2, fastcdc.ErrMinSize},
+		// small NormalSize rounded down to zero
+		{nil, 16 << 10, ErrSmallOptions},
+	} {
+		_, err := NewChunker("fastcdc", bytes.NewReader(nil), test.opts, test.ratio, has)
+		if test.expected == nil {
+			if err != nil {
+				t.Fatalf(`%+v / %d: options rejected: %s`, test.opts, test.ratio, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrSmallOptions) || !errors.Is(err, test.expected) {
+			t.Fatalf(`%+v / %d: expected ErrSmallOptions and %v, got %v`, test.opts, test.ratio, test.expected, err)
+		}
+		if test.expected != ErrSmallOptions && !errors.Is(err, chunkers.ErrInvalidOptions) {
+			t.Fatalf(`%+v / %d: expected ErrInvalidOptions, got %v`, test.opts, test.ratio, err)
+		}
+	}
+}
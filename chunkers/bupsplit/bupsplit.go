@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package bupsplit
+
+import (
+	"errors"
+	"math/bits"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func init() {
+	chunkers.MustRegister("bupsplit", newBupSplit)
+}
+
+var ErrNormalSize = errors.New("NormalSize is required and must be a power of two with 64B <= NormalSize <= 64KiB")
+var ErrMinSize = errors.New("MinSize must be 0 <= MinSize < NormalSize")
+var ErrMaxSize = errors.New("MaxSize is required and must be MaxSize <= 1GB && MaxSize > NormalSize")
+
+const (
+	windowSize = 64 // BUP_WINDOWSIZE
+	charOffset = 31 // ROLLSUM_CHAR_OFFSET
+
+	// log2 of bup's default tree fanout of 16
+	fanoutBits = 4
+
+	// maxNormalSize is the largest NormalSize the rollsum cuts at: s2
+	// sums the bytes of the window weighted by their position, and is
+	// about normally distributed with a standard deviation of 22K, so
+	// its low bits stop being uniform past 16 bits and it would cut
+	// larger chunks at MaxSize.
+	maxNormalSize = 64 * 1024
+)
+
+// BupSplit reproduces bup's hashsplit: the rsync-style rollsum over a
+// 64 bytes window, restarted at every chunk, with a boundary wherever the
+// low bits of s2 are all ones. NormalSize sets how many bits must match,
+// bup uses 13 bits (8KiB) and caps blobs at 32KiB, which are the defaults.
+// bup has no minimum size, hence the default of 0.
+//
+// Boundaries also carry bup's fanout level, reported by Level: bup counts
+// the extra ones following the matched bits in its digest, and every 4 of
+// them raise the boundary one level in its tree of blobs.
+type BupSplit struct {
+	level int
+}
+
+func newBupSplit() chunkers.ChunkerImplementation {
+	return &BupSplit{}
+}
+
+func (c *BupSplit) DefaultOptions() *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{
+		MinSize:    0,
+		NormalSize: 8 * 1024,
+		MaxSize:    32 * 1024,
+	}
+}
+
+func (c *BupSplit) Validate(options *chunkers.ChunkerOpts) error {
+	if options.NormalSize < 64 || options.NormalSize > maxNormalSize ||
+		options.NormalSize&(options.NormalSize-1) != 0 {
+		return ErrNormalSize
+	}
+	if options.MinSize < 0 || options.MinSize >= options.NormalSize {
+		return ErrMinSize
+	}
+	if options.MaxSize > 1024*1024*1024 || options.MaxSize <= options.NormalSize {
+		return ErrMaxSize
+	}
+	return nil
+}
+
+func (c *BupSplit) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	MinSize := options.MinSize
+	MaxSize := options.MaxSize
+
+	switch {
+	case n <= MinSize:
+		return n
+	case n >= MaxSize:
+		n = MaxSize
+	}
+
+	c.level = 0
+	blobBits := bits.TrailingZeros32(uint32(options.NormalSize))
+	mask := uint32(options.NormalSize - 1)
+
+	// the rollsum only depends on the last windowSize bytes, so rolling
+	// can start that far before the first acceptable cutpoint.
+	i := 0
+	if MinSize > windowSize {
+		i = MinSize - windowSize
+	}
+
+	s1 := uint32(windowSize * charOffset)
+	s2 := uint32(windowSize * (windowSize - 1) * charOffset)
+	start := i
+	for ; i < n; i++ {
+		var drop uint32
+		if i-start >= windowSize {
+			drop = uint32(data[i-windowSize])
+		}
+		s1 += uint32(data[i]) - drop
+		s2 += s1 - windowSize*(drop+charOffset)
+		if s2&mask == mask && i+1 >= MinSize {
+			c.level = level(s1<<16|s2&0xffff, blobBits)
+			return i + 1
+		}
+	}
+	return n
+}
+
+// level transcribes bupsplit_find_ofs and hashsplit.py: the first bit
+// past blobBits is skipped before counting ones, as bup does.
+func level(digest uint32, blobBits int) int {
+	ones := blobBits
+	for digest >>= blobBits; (digest>>1)&1 == 1; digest >>= 1 {
+		ones++
+	}
+	return (ones - blobBits) / fanoutBits
+}
+
+func (c *BupSplit) Level() int {
+	return c.level
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package bupsplit
+
+import (
+	mathrand2 "math/rand/v2"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+// rollsum is a literal transcription of bup's bupsplit.c, used as a
+// reference for the optimized Algorithm.
+type rollsum struct {
+	s1, s2 uint32
+	window [windowSize]byte
+	wofs   int
+}
+
+func (r *rollsum) init() {
+	r.s1 = windowSize * charOffset
+	r.s2 = windowSize * (windowSize - 1) * charOffset
+	r.window = [windowSize]byte{}
+	r.wofs = 0
+}
+
+func (r *rollsum) roll(ch byte) {
+	drop := uint32(r.window[r.wofs])
+	r.s1 += uint32(ch) - drop
+	r.s2 += r.s1 - windowSize*(drop+charOffset)
+	r.window[r.wofs] = ch
+	r.wofs = (r.wofs + 1) % windowSize
+}
+
+func findOfs(buf []byte, blobBits uint) int {
+	var r rollsum
+	r.init()
+	mask := uint32(1)<<blobBits - 1
+	for count := range buf {
+		r.roll(buf[count])
+		if r.s2&mask == mask {
+			return count + 1
+		}
+	}
+	return 0
+}
+
+func Test_Matches_Reference(t *testing.T) {
+	var seed [32]byte
+	data := make([]byte, 4<<20)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	c := newBupSplit().(*BupSplit)
+	opts := c.DefaultOptions()
+
+	chunks := 0
+	for len(data) > 0 {
+		n := min(len(data), opts.MaxSize)
+		expected := findOfs(data[:n], 13)
+		if expected == 0 {
+			expected = n
+		}
+		cutpoint := c.Algorithm(opts, data, n)
+		if cutpoint != expected {
+			t.Fatalf(`chunk %d: expected cutpoint %d, got %d`, chunks, expected, cutpoint)
+		}
+		data = data[cutpoint:]
+		chunks++
+	}
+
+	average := (4 << 20) / chunks
+	if average < 4096 || average > 12288 {
+		t.Fatalf(`unexpected average chunk size %d`, average)
+	}
+}
+
+func Test_MinSize_Skip(t *testing.T) {
+	var seed [32]byte
+	data := make([]byte, 4<<20)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	c := newBupSplit().(*BupSplit)
+	opts := &chunkers.ChunkerOpts{MinSize: 2048, NormalSize: 8192, MaxSize: 32768}
+	if err := c.Validate(opts); err != nil {
+		t.Fatal(err)
+	}
+
+	for len(data) > 0 {
+		n := min(len(data), opts.MaxSize)
+
+		// reference: first bup boundary at or past MinSize
+		expected := n
+		var r rollsum
+		r.init()
+		for i := 0; i < n; i++ {
+			r.roll(data[i])
+			if r.s2&8191 == 8191 && i+1 >= opts.MinSize {
+				expected = i + 1
+				break
+			}
+		}
+		if n <= opts.MinSize {
+			expected = n
+		}
+
+		cutpoint := c.Algorithm(opts, data, n)
+		if cutpoint != expected {
+			t.Fatalf(`expected cutpoint %d, got %d`, expected, cutpoint)
+		}
+		data = data[cutpoint:]
+	}
+}
+
+// findBits transcribes the bits counting of bupsplit_find_ofs, which bup's
+// hashsplit.py turns into a level with (bits - 13) // 4.
+func findBits(buf []byte, blobBits int) int {
+	var r rollsum
+	r.init()
+	for _, ch := range buf {
+		r.roll(ch)
+	}
+	rsum := r.s1<<16 | r.s2&0xffff
+	rsum >>= blobBits
+	bits := blobBits
+	for {
+		rsum >>= 1
+		if rsum&1 == 0 {
+			break
+		}
+		bits++
+	}
+	return bits
+}
+
+func Test_Levels(t *testing.T) {
+	var seed [32]byte
+	data := make([]byte, 16<<20)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	c := newBupSplit().(*BupSplit)
+	opts := c.DefaultOptions()
+
+	levels := 0
+	for len(data) > 0 {
+		n := min(len(data), opts.MaxSize)
+		cutpoint := c.Algorithm(opts, data, n)
+
+		expected := 0
+		if findOfs(data[:n], 13) != 0 {
+			expected = (findBits(data[:cutpoint], 13) - 13) / 4
+		}
+		if c.Level() != expected {
+			t.Fatalf(`expected level %d, got %d`, expected, c.Level())
+		}
+		if expected != 0 {
+			levels++
+		}
+		data = data[cutpoint:]
+	}
+	if levels == 0 {
+		t.Fatalf(`no boundary above level 0`)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package casync
+
+import (
+	"bytes"
+	"errors"
+	"math/bits"
+	mathrand2 "math/rand/v2"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func init() {
+	chunkers.MustRegister("casync", newCasync)
+}
+
+var ErrNormalSize = errors.New("NormalSize is required and must be 48B <= NormalSize <= 4MiB")
+var ErrMinSize = errors.New("MinSize is required and must be 48B <= MinSize <= 1GB && MinSize <= NormalSize")
+var ErrMaxSize = errors.New("MaxSize is required and must be 48B <= MaxSize <= 1GB && MaxSize >= NormalSize")
+
+const windowSize = 48
+
+// maxNormalSize bounds NormalSize to the range of casync's fit of the
+// discriminator, which overshoots past a few MiB and diverges at 9.3MiB.
+const maxNormalSize = 4 * 1024 * 1024
+
+// Casync is the buzhash chunker of casync and desync. It cuts where the
+// hash over a 48 bytes window, modulo a discriminator derived from
+// NormalSize, equals the discriminator minus one, so that the same
+// boundaries produce chunks matching .castr stores given the same sizes.
+type Casync struct {
+	// buzhash table derived from the key, if any
+	key   []byte
+	table *[256]uint32
+}
+
+func newCasync() chunkers.ChunkerImplementation {
+	return &Casync{}
+}
+
+func (c *Casync) DefaultOptions() *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{
+		MinSize:    16 * 1024,
+		MaxSize:    256 * 1024,
+		NormalSize: 64 * 1024,
+	}
+}
+
+func (c *Casync) Validate(options *chunkers.ChunkerOpts) error {
+	if options.NormalSize < windowSize || options.NormalSize > maxNormalSize {
+		return ErrNormalSize
+	}
+	if options.MinSize < windowSize || options.MinSize > 1024*1024*1024 || options.MinSize > options.NormalSize {
+		return ErrMinSize
+	}
+	if options.MaxSize < windowSize || options.MaxSize > 1024*1024*1024 || options.MaxSize < options.NormalSize {
+		return ErrMaxSize
+	}
+	return nil
+}
+
+// discriminator is casync's empirical fit of the modulus yielding chunks
+// of average size avg once MinSize and MaxSize are accounted for.
+func discriminator(avg int) uint32 {
+	return uint32(float64(avg) / (-1.42888852e-7*float64(avg) + 1.33237515))
+}
+
+// tableFor returns the buzhash table in effect for options, casync's own
+// unless ChunkerOpts.Key is set.
+func (c *Casync) tableFor(options *chunkers.ChunkerOpts) *[256]uint32 {
+	if len(options.Key) == 0 {
+		return &hashTable
+	}
+	if c.table == nil || !bytes.Equal(c.key, options.Key) {
+		rng := mathrand2.NewChaCha8(chunkers.DeriveKey(options.Key, "buzhash table"))
+		c.key = bytes.Clone(options.Key)
+		c.table = new([256]uint32)
+		for i := range c.table {
+			c.table[i] = uint32(rng.Uint64())
+		}
+	}
+	return c.table
+}
+
+// Entropy reports the buzhash table.
+func (c *Casync) Entropy(options *chunkers.ChunkerOpts) []chunkers.EntropyInput {
+	return []chunkers.EntropyInput{chunkers.NewEntropyInput("buzhash table", c.tableFor(options))}
+}
+
+// Keyed marks the buzhash table as derived from ChunkerOpts.Key.
+func (c *Casync) Keyed() {}
+
+func (c *Casync) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	MinSize := options.MinSize
+	MaxSize := options.MaxSize
+
+	switch {
+	case n <= MinSize:
+		return n
+	case n >= MaxSize:
+		n = MaxSize
+	}
+
+	d := discriminator(options.NormalSize)
+	table := c.tableFor(options)
+
+	var h uint32
+	for i, b := range data[MinSize-windowSize : MinSize] {
+		h ^= bits.RotateLeft32(table[b], windowSize-i-1)
+	}
+
+	for i := MinSize; i < n; i++ {
+		h = bits.RotateLeft32(h, 1) ^
+			bits.RotateLeft32(table[data[i-windowSize]], windowSize) ^
+			table[data[i]]
+
+		if h%d == d-1 {
+			return i + 1
+		}
+	}
+	return n
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package casync
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	mathrand2 "math/rand/v2"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+// Cutpoints produced by desync v0.9.6 over the same data, as the SHA-256
+// of the little-endian uint64 chunk ends.
+var desyncVectors = []struct {
+	opts     chunkers.ChunkerOpts
+	chunks   int
+	expected string
+}{
+	{chunkers.ChunkerOpts{MinSize: 16 << 10, NormalSize: 64 << 10, MaxSize: 256 << 10}, 132, "3c63212fa9f6d52f594a2fc6b57ab8341be876aae1b087a7807126ab7d520abb"},
+	{chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 32 << 10}, 1007, "b3dad0c79423775d2aa62d17bc85533803e7c2c42179cf3c2ef35733a86d73ec"},
+	{chunkers.ChunkerOpts{MinSize: 48, NormalSize: 1000, MaxSize: 4000}, 10626, "cb170f9fd8cd5a70d801e9e12386373a2d3a4cc77bacab62c399104caf7c9c1a"},
+}
+
+func Test_DesyncCompatibility(t *testing.T) {
+	var seed [32]byte
+	data := make([]byte, 8<<20)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	c := newCasync()
+	for _, vector := range desyncVectors {
+		if err := c.Validate(&vector.opts); err != nil {
+			t.Fatalf(`%v: %s`, vector.opts, err)
+		}
+
+		hasher := sha256.New()
+		chunks := 0
+		for offset := 0; offset < len(data); chunks++ {
+			remaining := data[offset:]
+			offset += c.Algorithm(&vector.opts, remaining, min(len(remaining), vector.opts.MaxSize))
+			binary.Write(hasher, binary.LittleEndian, uint64(offset))
+		}
+
+		if chunks != vector.chunks {
+			t.Fatalf(`%v: expected %d chunks, got %d`, vector.opts, vector.chunks, chunks)
+		}
+		if digest := hex.EncodeToString(hasher.Sum(nil)); digest != vector.expected {
+			t.Fatalf(`%v: expected cutpoints digest %s, got %s`, vector.opts, vector.expected, digest)
+		}
+	}
+}
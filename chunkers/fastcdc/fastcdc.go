@@ -18,26 +18,89 @@ package fastcdc
 
 import (
 	"errors"
+	"math/bits"
 	"unsafe"
 
 	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
 )
 
 func init() {
-	chunkers.Register("fastcdc", newFastCDC)
+	chunkers.MustRegister("fastcdc", newFastCDC)
+	chunkers.MustRegister("fastcdc@v2", newFastCDCv2)
+	chunkers.MustRegister("fastcdc2020", newFastCDC2020)
+	chunkers.MustRegister("fastcdc2020@v2", newFastCDC2020v2)
 }
 
 var ErrNormalSize = errors.New("NormalSize is required and must be 64B <= NormalSize <= 1GB")
 var ErrMinSize = errors.New("MinSize is required and must be 64B <= MinSize <= 1GB && MinSize < NormalSize")
 var ErrMaxSize = errors.New("MaxSize is required and must be 64B <= MaxSize <= 1GB && MaxSize > NormalSize")
+var ErrNormalizationLevel = errors.New("NormalizationLevel must be -1 <= NormalizationLevel <= 3")
+
+// DefaultNormalizationLevel is the level used when NormalizationLevel is
+// zero, NC2 in the FastCDC paper.
+const DefaultNormalizationLevel = 2
+
+// masks are indexed by their number of one bits: normalization level k
+// cuts with masks[b+k] before NormalSize and masks[b-k] after it, b being
+// 13 for a NormalSize of 8KiB as in the paper. Masks below 10 bits drop
+// the most significant bit of the next one, masks above 16 bits add one
+// to the previous one, below bit 62 so that fastcdc2020 can shift them.
+var masks = [34]uint64{
+	1:  0x0000000000010000,
+	2:  0x0000000000030000,
+	3:  0x0000000000130000,
+	4:  0x0000000000530000,
+	5:  0x0000000001530000,
+	6:  0x0000000003530000,
+	7:  0x0000010003530000,
+	8:  0x0000090003530000,
+	9:  0x0000190003530000,
+	10: 0x0000590003530000,
+	11: 0x0000d90003530000,
+	12: 0x0000d90103530000,
+	13: 0x0000d90303530000,
+	14: 0x0000d90703530000,
+	15: 0x0003590703530000,
+	16: 0x0003d90703530000,
+	17: 0x000bd90703530000,
+	18: 0x000bd90713530000,
+	19: 0x008bd90713530000,
+	20: 0x008bd91713530000,
+	21: 0x088bd91713530000,
+	22: 0x088bd917135b0000,
+	23: 0x08abd917135b0000,
+	24: 0x08abd917535b0000,
+	25: 0x0aabd917535b0000,
+	26: 0x0aabd957535b0000,
+	27: 0x2aabd957535b0000,
+	28: 0x2aabd9575b5b0000,
+	29: 0x2aabdd575b5b0000,
+	30: 0x2aabfd575b5b0000,
+	31: 0x2aabfd5f5b5b0000,
+	32: 0x2aaffd5f5b5b0000,
+	33: 0x2aaffddf5b5b0000,
+}
+
+// LargeNormalSize is the NormalSize from which the masks of v2 grow with
+// it again, for the chunks of several MiB of media and disk images: the
+// masks of the paper would cut them shortly after MinSize.
+const LargeNormalSize = 4 * 1024 * 1024
 
 type FastCDC struct {
+	keyed KeyedTable
+
+	// scaled scales the masks with NormalSize, as registered by v2
+	scaled bool
 }
 
 func newFastCDC() chunkers.ChunkerImplementation {
 	return &FastCDC{}
 }
 
+func newFastCDCv2() chunkers.ChunkerImplementation {
+	return &FastCDC{scaled: true}
+}
+
 func (c *FastCDC) DefaultOptions() *chunkers.ChunkerOpts {
 	return &chunkers.ChunkerOpts{
 		MinSize:    2 * 1024,
@@ -56,18 +119,75 @@ func (c *FastCDC) Validate(options *chunkers.ChunkerOpts) error {
 	if options.MaxSize < 64 || options.MaxSize > 1024*1024*1024 || options.MaxSize <= options.NormalSize {
 		return ErrMaxSize
 	}
+	if level := normalizationLevel(options); level < -1 || level > 3 {
+		return ErrNormalizationLevel
+	}
+	if _, ok := options.Extension.(*Options); options.Extension != nil && !ok {
+		return ErrOptions
+	}
 	return nil
 }
 
+// normalizedMasks returns the masks used before and after NormalSize, the
+// masks of the paper whatever NormalSize in v1. In v2, masks lose a bit
+// every time NormalSize halves below 8KiB, as the masks of the paper
+// would otherwise cut most small chunks at MaxSize, and gain one for
+// every doubling past 8KiB from LargeNormalSize. In between, they stay
+// those of the paper so that cutpoints match v1.
+func (c *FastCDC) normalizedMasks(options *chunkers.ChunkerOpts) (uint64, uint64) {
+	level := normalizationLevel(options)
+	switch level {
+	case 0:
+		level = DefaultNormalizationLevel
+	case -1:
+		level = 0
+	}
+	b := 13
+	if c.scaled {
+		b = bits.Len(uint(options.NormalSize)) - 1
+		if options.NormalSize < LargeNormalSize {
+			b = min(13, b)
+		}
+	}
+	return masks[b+level], masks[b-level]
+}
+
+// normalizationLevel returns the normalization level set for options, the
+// one of the Extension taking precedence.
+func normalizationLevel(options *chunkers.ChunkerOpts) int {
+	if ext, ok := options.Extension.(*Options); ok && ext.NormalizationLevel != 0 {
+		return ext.NormalizationLevel
+	}
+	return options.NormalizationLevel
+}
+
+// ResolveOptions sets the NormalizationLevel in effect in the Extension.
+func (c *FastCDC) ResolveOptions(options *chunkers.ChunkerOpts) {
+	level := normalizationLevel(options)
+	if level == 0 {
+		level = DefaultNormalizationLevel
+	}
+	extension(options).NormalizationLevel = level
+	options.NormalizationLevel = 0
+}
+
+// Entropy reports the Gear table in effect.
+func (c *FastCDC) Entropy(options *chunkers.ChunkerOpts) []chunkers.EntropyInput {
+	return []chunkers.EntropyInput{chunkers.NewEntropyInput("gear table", c.gearTable(options))}
+}
+
+// Keyed marks the Gear table as derived from ChunkerOpts.Key.
+func (c *FastCDC) Keyed() {}
+
+// Unsafe marks the input as read through unsafe pointers.
+func (c *FastCDC) Unsafe() {}
+
 func (c *FastCDC) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
 	MinSize := options.MinSize
 	MaxSize := options.MaxSize
 	NormalSize := options.NormalSize
 
-	const (
-		MaskS = uint64(0x0003590703530000)
-		MaskL = uint64(0x0000d90003530000)
-	)
+	MaskS, MaskL := c.normalizedMasks(options)
 
 	switch {
 	case n <= MinSize:
@@ -78,6 +198,7 @@ func (c *FastCDC) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) i
 		NormalSize = n
 	}
 
+	g := c.gearTable(options)
 	fp := uint64(0)
 	i := MinSize
 	mask := MaskS
@@ -87,7 +208,7 @@ func (c *FastCDC) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) i
 		if i == NormalSize {
 			mask = MaskL
 		}
-		fp = (fp << 1) + G[*(*byte)(p)]
+		fp = (fp << 1) + g[*(*byte)(p)]
 		if (fp & mask) == 0 {
 			return i
 		}
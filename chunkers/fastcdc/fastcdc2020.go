@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package fastcdc
+
+import (
+	"unsafe"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+// gLS is the Gear table shifted left by one bit, see FastCDC2020.
+var gLS [256]uint64
+
+func init() {
+	for i := range G {
+		gLS[i] = G[i] << 1
+	}
+}
+
+// FastCDC2020 is the "rolling two bytes each time" optimization from the
+// 2020 revision of the FastCDC paper. The fingerprint is shifted by two
+// bits per iteration, adding the first byte from a pre-shifted table and
+// testing it against a pre-shifted mask, so the loop runs half as many
+// iterations. The masks leave the top bits clear, so no bit is lost to
+// the extra shift and cutpoints are identical to fastcdc's: only the
+// speed differs.
+type FastCDC2020 struct {
+	FastCDC
+
+	// pre-shifted copy of a table other than G
+	table   *[256]uint64
+	tableLS [256]uint64
+}
+
+// shiftedTable returns g shifted left by one bit.
+func (c *FastCDC2020) shiftedTable(g *[256]uint64) *[256]uint64 {
+	if g == &G {
+		return &gLS
+	}
+	if c.table != g {
+		c.table = g
+		for i := range g {
+			c.tableLS[i] = g[i] << 1
+		}
+	}
+	return &c.tableLS
+}
+
+func newFastCDC2020() chunkers.ChunkerImplementation {
+	return &FastCDC2020{}
+}
+
+func newFastCDC2020v2() chunkers.ChunkerImplementation {
+	return &FastCDC2020{FastCDC: FastCDC{scaled: true}}
+}
+
+func (c *FastCDC2020) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	MinSize := options.MinSize
+	MaxSize := options.MaxSize
+	NormalSize := options.NormalSize
+
+	switch {
+	case n <= MinSize:
+		return n
+	case n >= MaxSize:
+		n = MaxSize
+	case n <= NormalSize:
+		NormalSize = n
+	}
+
+	MaskS, MaskL := c.normalizedMasks(options)
+	MaskSLS, MaskLLS := MaskS<<1, MaskL<<1
+
+	g := c.gearTable(options)
+	gls := c.shiftedTable(g)
+
+	fp := uint64(0)
+	i := MinSize
+	p := unsafe.Pointer(&data[i])
+	for ; i+1 < NormalSize; i += 2 {
+		fp = (fp << 2) + gls[*(*byte)(p)]
+		if (fp & MaskSLS) == 0 {
+			return i
+		}
+		fp += g[*(*byte)(unsafe.Add(p, 1))]
+		if (fp & MaskS) == 0 {
+			return i + 1
+		}
+		p = unsafe.Add(p, 2)
+	}
+	if i < NormalSize {
+		// odd distance to NormalSize, roll a single byte
+		fp = (fp << 1) + g[*(*byte)(p)]
+		if (fp & MaskS) == 0 {
+			return i
+		}
+		i++
+		p = unsafe.Add(p, 1)
+	}
+	for ; i+1 < n; i += 2 {
+		fp = (fp << 2) + gls[*(*byte)(p)]
+		if (fp & MaskLLS) == 0 {
+			return i
+		}
+		fp += g[*(*byte)(unsafe.Add(p, 1))]
+		if (fp & MaskL) == 0 {
+			return i + 1
+		}
+		p = unsafe.Add(p, 2)
+	}
+	if i < n {
+		fp = (fp << 1) + g[*(*byte)(p)]
+		if (fp & MaskL) == 0 {
+			return i
+		}
+	}
+	return n
+}
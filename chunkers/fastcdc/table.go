@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package fastcdc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	mathrand2 "math/rand/v2"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+var ErrOptions = errors.New("Extension must be *fastcdc.Options")
+
+// Options tunes fastcdc and fastcdc2020 when passed as
+// ChunkerOpts.Extension.
+type Options struct {
+	// GearTable replaces G when set. Systems sharing a private table, such
+	// as one generated from a secret seed, cut at the same boundaries
+	// while others cannot predict them from known content.
+	GearTable *[256]uint64
+
+	// NormalizationLevel sets how far normalized chunking tightens the
+	// cut condition before NormalSize and relaxes it after, from 1 to 3,
+	// trading deduplication for a narrower chunk size distribution. -1
+	// disables normalization and zero selects DefaultNormalizationLevel.
+	NormalizationLevel int
+}
+
+// Identity appends NormalizationLevel and the contents of GearTable to
+// dst, see chunkers.IdentityExtension.
+func (o *Options) Identity(dst []byte) []byte {
+	dst = binary.LittleEndian.AppendUint64(dst, uint64(o.NormalizationLevel))
+	if o.GearTable == nil {
+		return append(dst, 0)
+	}
+	dst = append(dst, 1)
+	for _, value := range o.GearTable {
+		dst = binary.LittleEndian.AppendUint64(dst, value)
+	}
+	return dst
+}
+
+// extension returns a copy of the Options in opts to modify, and sets it
+// as the Extension of opts.
+func extension(opts *chunkers.ChunkerOpts) *Options {
+	ext := &Options{}
+	if current, ok := opts.Extension.(*Options); ok {
+		*ext = *current
+	}
+	opts.Extension = ext
+	return ext
+}
+
+// WithGearTable sets the GearTable of the Extension.
+func WithGearTable(table *[256]uint64) chunkers.Option {
+	return func(opts *chunkers.ChunkerOpts) { extension(opts).GearTable = table }
+}
+
+// WithNormalization sets the NormalizationLevel of the Extension.
+func WithNormalization(level int) chunkers.Option {
+	return func(opts *chunkers.ChunkerOpts) { extension(opts).NormalizationLevel = level }
+}
+
+// GenerateGearTable derives a Gear table from seed, the same seed yielding
+// the same table on every platform.
+func GenerateGearTable(seed []byte) *[256]uint64 {
+	rng := mathrand2.NewChaCha8(sha256.Sum256(seed))
+	table := new([256]uint64)
+	for i := range table {
+		table[i] = rng.Uint64()
+	}
+	return table
+}
+
+// KeyedTable caches the Gear table derived from a ChunkerOpts.Key, shared
+// by every Gear-based algorithm so that they keep rolling the same table
+// once keyed.
+type KeyedTable struct {
+	key   []byte
+	table *[256]uint64
+}
+
+// Table returns the Gear table derived from key, or G if key is empty.
+func (k *KeyedTable) Table(key []byte) *[256]uint64 {
+	if len(key) == 0 {
+		return &G
+	}
+	if k.table == nil || !bytes.Equal(k.key, key) {
+		seed := chunkers.DeriveKey(key, "gear table")
+		k.key = bytes.Clone(key)
+		k.table = GenerateGearTable(seed[:])
+	}
+	return k.table
+}
+
+// gearTable returns the Gear table in effect for options, a table passed
+// as extension taking precedence over one derived from the key.
+func (c *FastCDC) gearTable(options *chunkers.ChunkerOpts) *[256]uint64 {
+	if ext, ok := options.Extension.(*Options); ok && ext.GearTable != nil {
+		return ext.GearTable
+	}
+	return c.keyed.Table(options.Key)
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package fixed
+
+import (
+	"errors"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func init() {
+	chunkers.MustRegister("fixed", newFixed)
+}
+
+var ErrNormalSize = errors.New("NormalSize is required and must be 64B <= NormalSize <= 1GB")
+var ErrMinSize = errors.New("MinSize is required and must be 64B <= MinSize <= 1GB && MinSize <= NormalSize")
+var ErrMaxSize = errors.New("MaxSize is required and must be 64B <= MaxSize <= 1GB && MaxSize >= NormalSize")
+
+// Fixed cuts every NormalSize bytes regardless of content, the baseline
+// content-defined chunking is measured against: a single inserted byte
+// shifts every following boundary. MinSize and MaxSize only bound the
+// last chunk and the chunker's buffer.
+type Fixed struct {
+}
+
+func newFixed() chunkers.ChunkerImplementation {
+	return &Fixed{}
+}
+
+func (c *Fixed) DefaultOptions() *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{
+		MinSize:    2 * 1024,
+		MaxSize:    64 * 1024,
+		NormalSize: 8 * 1024,
+	}
+}
+
+func (c *Fixed) Validate(options *chunkers.ChunkerOpts) error {
+	if options.NormalSize < 64 || options.NormalSize > 1024*1024*1024 {
+		return ErrNormalSize
+	}
+	if options.MinSize < 64 || options.MinSize > 1024*1024*1024 || options.MinSize > options.NormalSize {
+		return ErrMinSize
+	}
+	if options.MaxSize < 64 || options.MaxSize > 1024*1024*1024 || options.MaxSize < options.NormalSize {
+		return ErrMaxSize
+	}
+	return nil
+}
+
+func (c *Fixed) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	return min(n, options.NormalSize)
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package fixed
+
+import (
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_Cutpoints(t *testing.T) {
+	c := newFixed()
+	opts := c.DefaultOptions()
+	if err := c.Validate(opts); err != nil {
+		t.Fatalf(`default options: %s`, err)
+	}
+
+	data := make([]byte, 100<<10)
+	if cutpoint := c.Algorithm(opts, data, len(data)); cutpoint != opts.NormalSize {
+		t.Fatalf(`expected a cut at %d, got %d`, opts.NormalSize, cutpoint)
+	}
+	if cutpoint := c.Algorithm(opts, data, 5000); cutpoint != 5000 {
+		t.Fatalf(`expected the 5000 bytes tail, got %d`, cutpoint)
+	}
+
+	if err := c.Validate(&chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 4 << 10}); err != ErrMaxSize {
+		t.Fatalf(`expected ErrMaxSize, got %v`, err)
+	}
+}
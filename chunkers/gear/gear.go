@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gear
+
+import (
+	"errors"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+)
+
+func init() {
+	chunkers.MustRegister("gear", newGear)
+}
+
+var ErrNormalSize = errors.New("NormalSize is required and must be 64B <= NormalSize <= 1GB")
+var ErrMinSize = errors.New("MinSize is required and must be 64B <= MinSize <= 1GB && MinSize < NormalSize")
+var ErrMaxSize = errors.New("MaxSize is required and must be 64B <= MaxSize <= 1GB && MaxSize > NormalSize")
+
+// Gear is the original Gear-based CDC that FastCDC builds upon: a single
+// mask, no normalization. It rolls the same Gear table as fastcdc so both
+// can be compared on equal terms. The mask uses the most significant bits
+// of the fingerprint, which depend on the last 64 bytes. Its width is the
+// largest power of two not above NormalSize - MinSize, which is how far
+// past MinSize cuts happen on average. Gear runs on chunkers.Windowless
+// with normalization off.
+type Gear struct {
+	chunkers.Windowless
+	keyed fastcdc.KeyedTable
+	table *[256]uint64
+}
+
+func newGear() chunkers.ChunkerImplementation {
+	return &Gear{}
+}
+
+func (c *Gear) Validate(options *chunkers.ChunkerOpts) error {
+	if options.NormalSize == 0 || options.NormalSize < 64 || options.NormalSize > 1024*1024*1024 {
+		return ErrNormalSize
+	}
+	if options.MinSize < 64 || options.MinSize > 1024*1024*1024 || options.MinSize >= options.NormalSize {
+		return ErrMinSize
+	}
+	if options.MaxSize < 64 || options.MaxSize > 1024*1024*1024 || options.MaxSize <= options.NormalSize {
+		return ErrMaxSize
+	}
+	return nil
+}
+
+// Entropy reports the Gear table shared with fastcdc.
+func (c *Gear) Entropy(options *chunkers.ChunkerOpts) []chunkers.EntropyInput {
+	return []chunkers.EntropyInput{chunkers.NewEntropyInput("gear table", c.keyed.Table(options.Key))}
+}
+
+// Keyed marks the Gear table as derived from ChunkerOpts.Key.
+func (c *Gear) Keyed() {}
+
+// roll steps the fingerprint over b with the table of the options being
+// chunked with.
+func (c *Gear) roll(fingerprint uint64, b byte) uint64 {
+	return fingerprint<<1 + c.table[b]
+}
+
+func (c *Gear) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	if c.Roll == nil {
+		c.Windowless = chunkers.Windowless{Roll: c.roll, Unnormalized: true}
+	}
+	c.table = c.keyed.Table(options.Key)
+	return c.Windowless.Algorithm(options, data, n)
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package gear
+
+import (
+	mathrand2 "math/rand/v2"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_Average_Size(t *testing.T) {
+	var seed [32]byte
+	data := make([]byte, 16<<20)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	c := newGear().(*Gear)
+	for _, opts := range []*chunkers.ChunkerOpts{
+		c.DefaultOptions(),
+		{MinSize: 16 << 10, NormalSize: 64 << 10, MaxSize: 256 << 10},
+	} {
+		if err := c.Validate(opts); err != nil {
+			t.Fatal(err)
+		}
+
+		chunks := 0
+		for remaining := data; len(remaining) > 0; chunks++ {
+			cutpoint := c.Algorithm(opts, remaining, min(len(remaining), opts.MaxSize))
+			if cutpoint > opts.MaxSize || (cutpoint < opts.MinSize && cutpoint != len(remaining)) {
+				t.Fatalf(`cutpoint %d out of bounds`, cutpoint)
+			}
+			remaining = remaining[cutpoint:]
+		}
+
+		average := len(data) / chunks
+		if average < opts.NormalSize/2 || average > opts.NormalSize {
+			t.Fatalf(`average chunk size %d too far from %d`, average, opts.NormalSize)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package hierarchy
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+var ErrFanout = errors.New("fanout must be a power of two >= 2")
+var ErrDigestSize = errors.New("Hash must produce digests of at least 4 bytes")
+
+// DefaultFanout is the average number of chunks per superchunk used when
+// none is given.
+const DefaultFanout = 16
+
+// maxRatio bounds superchunks to maxRatio times the fanout in chunks.
+const maxRatio = 8
+
+// Chunker runs a second, coarser content-defined pass over the digests of
+// the chunks of a stream, grouping consecutive chunks into superchunks:
+// a superchunk ends after a chunk whose digest has its low bits set, so
+// that an edit only changes the superchunks around it, as with chunks.
+// Two-level indexes keep one entry per superchunk and only look into the
+// ones that are not known already.
+type Chunker struct {
+	chunker *chunkers.Chunker
+	mask    uint32
+	max     int
+
+	// the superchunk being accumulated, its digest hashing the digests
+	// of its chunks
+	hasher hash.Hash
+	digest []byte
+	offset uint64
+	length uint64
+	chunks int
+}
+
+// NewChunker returns a chunker cutting chunks with opts, or the
+// algorithm's defaults if nil, and grouping them into superchunks of
+// fanout chunks on average, or DefaultFanout if zero, and of at most 8
+// times as many. Digests are computed with opts.Hash.
+func NewChunker(algorithm string, reader io.Reader, opts *chunkers.ChunkerOpts, fanout int) (*Chunker, error) {
+	if fanout == 0 {
+		fanout = DefaultFanout
+	}
+	if fanout < 2 || fanout&(fanout-1) != 0 {
+		return nil, ErrFanout
+	}
+
+	newHash := sha256.New
+	if opts != nil && opts.Hash != nil {
+		newHash = opts.Hash
+	}
+	hasher := newHash()
+	if hasher.Size() < 4 {
+		return nil, ErrDigestSize
+	}
+
+	chunker, err := chunkers.NewChunker(algorithm, reader, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Chunker{
+		chunker: chunker,
+		mask:    uint32(fanout - 1),
+		max:     maxRatio * fanout,
+		hasher:  hasher,
+		digest:  make([]byte, 0, hasher.Size()),
+	}, nil
+}
+
+// Close behaves like chunkers.Chunker.Close.
+func (c *Chunker) Close() error {
+	return c.chunker.Close()
+}
+
+// Split calls chunk for every chunk with its digest, as
+// chunkers.Chunker.SplitDigest does, and super for every superchunk once
+// its last chunk was passed to chunk, with its offset and length in the
+// stream, its number of chunks and its digest. Digests are only valid
+// until the callback returns.
+func (c *Chunker) Split(chunk func(offset, length uint64, data []byte, digest []byte) error, super func(offset, length uint64, chunks int, digest []byte) error) error {
+	c.hasher.Reset()
+	c.offset, c.length, c.chunks = 0, 0, 0
+
+	err := c.chunker.SplitDigest(func(offset, length uint64, data []byte, digest []byte) error {
+		if err := chunk(offset, length, data, digest); err != nil {
+			return err
+		}
+
+		if c.chunks == 0 {
+			c.offset = offset
+		}
+		c.hasher.Write(digest)
+		c.length += length
+		c.chunks++
+
+		if binary.LittleEndian.Uint32(digest)&c.mask == c.mask || c.chunks == c.max {
+			return c.flush(super)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if c.chunks != 0 {
+		return c.flush(super)
+	}
+	return nil
+}
+
+// flush ends the superchunk being accumulated.
+func (c *Chunker) flush(super func(offset, length uint64, chunks int, digest []byte) error) error {
+	err := super(c.offset, c.length, c.chunks, c.hasher.Sum(c.digest[:0]))
+	c.hasher.Reset()
+	c.length, c.chunks = 0, 0
+	return err
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package hierarchy
+
+import (
+	"bytes"
+	"crypto/sha256"
+	mathrand2 "math/rand/v2"
+	"testing"
+
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+)
+
+type superchunk struct {
+	offset, length uint64
+	chunks         int
+	digest         string
+}
+
+func split(t *testing.T, data []byte, fanout int) []superchunk {
+	chunker, err := NewChunker("fastcdc", bytes.NewReader(data), nil, fanout)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	var supers []superchunk
+	var digests []byte
+	var offset uint64
+	chunks := 0
+	err = chunker.Split(func(offset, length uint64, data []byte, digest []byte) error {
+		digests = append(digests, digest...)
+		chunks++
+		return nil
+	}, func(superOffset, length uint64, count int, digest []byte) error {
+		if superOffset != offset {
+			t.Fatalf(`superchunk at offset %d, expected %d`, superOffset, offset)
+		}
+		if count != chunks {
+			t.Fatalf(`superchunk of %d chunks, %d were passed`, count, chunks)
+		}
+		if sum := sha256.Sum256(digests); !bytes.Equal(digest, sum[:]) {
+			t.Fatalf(`superchunk digest is not that of its chunk digests`)
+		}
+		supers = append(supers, superchunk{superOffset, length, count, string(digest)})
+		offset += length
+		digests, chunks = digests[:0], 0
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if offset != uint64(len(data)) || chunks != 0 {
+		t.Fatalf(`superchunks do not cover the input`)
+	}
+	return supers
+}
+
+func Test_Hierarchy(t *testing.T) {
+	var seed [32]byte
+	original := make([]byte, 32<<20)
+	mathrand2.NewChaCha8(seed).Read(original)
+
+	supers := split(t, original, 0)
+	chunks := 0
+	for _, s := range supers {
+		if s.chunks > maxRatio*DefaultFanout {
+			t.Fatalf(`superchunk of %d chunks`, s.chunks)
+		}
+		chunks += s.chunks
+	}
+	if average := float64(chunks) / float64(len(supers)); average < DefaultFanout/2 || average > 2*DefaultFanout {
+		t.Fatalf(`%.1f chunks per superchunk on average`, average)
+	}
+
+	// an edit only changes the superchunks around it
+	mutated := append([]byte{}, original...)
+	copy(mutated[16<<20:], "an edit in the middle of the stream")
+	known := make(map[string]struct{})
+	for _, s := range supers {
+		known[s.digest] = struct{}{}
+	}
+	changed := 0
+	for _, s := range split(t, mutated, 0) {
+		if _, exists := known[s.digest]; !exists {
+			changed++
+		}
+	}
+	if changed == 0 || changed > 2 {
+		t.Fatalf(`%d superchunks changed`, changed)
+	}
+}
+
+func Test_Fanout(t *testing.T) {
+	for _, fanout := range []int{-1, 1, 3, 12} {
+		if _, err := NewChunker("fastcdc", bytes.NewReader(nil), nil, fanout); err != ErrFanout {
+			t.Fatalf(`fanout %d: expected ErrFanout, got %v`, fanout, err)
+		}
+	}
+}
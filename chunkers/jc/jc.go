@@ -19,13 +19,16 @@ package jc
 import (
 	"errors"
 	"math"
+	"math/bits"
 	"unsafe"
 
 	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
 )
 
 func init() {
-	chunkers.Register("jc", newJC)
+	chunkers.MustRegister("jc", newJC)
+	chunkers.MustRegister("jc@v2", newJCv2)
 }
 
 var errNormalSize = errors.New("NormalSize is required and must be 64B <= NormalSize <= 1GB")
@@ -35,12 +38,20 @@ var errMaxSize = errors.New("MaxSize is required and must be 64B <= MaxSize <= 1
 type JC struct {
 	computeJumpLength bool
 	jumpLength        int
+	keyed             fastcdc.KeyedTable
+
+	// scaled scales the masks with NormalSize, as registered by v2
+	scaled bool
 }
 
 func newJC() chunkers.ChunkerImplementation {
 	return &JC{}
 }
 
+func newJCv2() chunkers.ChunkerImplementation {
+	return &JC{scaled: true}
+}
+
 func (c *JC) DefaultOptions() *chunkers.ChunkerOpts {
 	return &chunkers.ChunkerOpts{
 		MinSize:    2 * 1024,
@@ -62,15 +73,63 @@ func (c *JC) Validate(options *chunkers.ChunkerOpts) error {
 	return nil
 }
 
+// table returns the Gear table in effect for options, once keyed the one
+// shared with fastcdc.
+func (c *JC) table(options *chunkers.ChunkerOpts) *[256]uint64 {
+	if len(options.Key) != 0 {
+		return c.keyed.Table(options.Key)
+	}
+	return &G
+}
+
+// largeBits are the bits the masks gain from fastcdc.LargeNormalSize, in
+// the order fastcdc adds them to its own.
+var largeBits = [...]int{51, 28, 55, 36, 59, 19, 53, 30, 57, 38, 61, 27, 42, 45, 35, 50, 39}
+
+// masks returns the cut and jump masks for normalSize, those of the
+// paper whatever normalSize in v1. In v2, both lose their most
+// significant bit every time normalSize halves below 8KiB, as the masks
+// of the paper would otherwise cut most small chunks at MaxSize, and
+// gain a bit for every doubling past 8KiB from fastcdc.LargeNormalSize,
+// as they would otherwise cut large chunks shortly after MinSize. The
+// jump mask remains a subset of the cut mask.
+func (c *JC) masks(normalSize int) (uint64, uint64) {
+	maskC, maskJ := uint64(0x590003570000), uint64(0x590003560000)
+	if !c.scaled {
+		return maskC, maskJ
+	}
+	b := bits.Len(uint(normalSize)) - 1
+	for i := b; i < 13; i++ {
+		high := uint64(1) << (bits.Len64(maskJ) - 1)
+		maskC &^= high
+		maskJ &^= high
+	}
+	if normalSize >= fastcdc.LargeNormalSize {
+		for _, bit := range largeBits[:b-13] {
+			maskC |= 1 << bit
+			maskJ |= 1 << bit
+		}
+	}
+	return maskC, maskJ
+}
+
+// Entropy reports the Gear table.
+func (c *JC) Entropy(options *chunkers.ChunkerOpts) []chunkers.EntropyInput {
+	return []chunkers.EntropyInput{chunkers.NewEntropyInput("gear table", c.table(options))}
+}
+
+// Keyed marks the Gear table as derived from ChunkerOpts.Key.
+func (c *JC) Keyed() {}
+
+// Unsafe marks the input as read through unsafe pointers.
+func (c *JC) Unsafe() {}
+
 func (c *JC) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
 	MinSize := options.MinSize
 	MaxSize := options.MaxSize
 	NormalSize := options.NormalSize
 
-	const (
-		MaskC = uint64(0x590003570000)
-		MaskJ = uint64(0x590003560000)
-	)
+	MaskC, MaskJ := c.masks(NormalSize)
 
 	switch {
 	case n <= MinSize:
@@ -81,6 +140,7 @@ func (c *JC) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
 		NormalSize = n
 	}
 
+	g := c.table(options)
 	fp := uint64(0)
 	i := MinSize
 
@@ -93,7 +153,7 @@ func (c *JC) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
 	var p unsafe.Pointer
 	for ; i < n; i++ {
 		p = unsafe.Pointer(&data[i])
-		fp = (fp << 1) + G[*(*byte)(p)]
+		fp = (fp << 1) + g[*(*byte)(p)]
 		if (fp & MaskJ) == 0 {
 			if (fp & MaskC) == 0 {
 				return i
@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package maxp
+
+import (
+	"encoding/binary"
+	"errors"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func init() {
+	chunkers.MustRegister("maxp", newMAXP)
+}
+
+var ErrNormalSize = errors.New("NormalSize is required and must be 64B <= NormalSize <= 1GB")
+var ErrMinSize = errors.New("MinSize is required and must be 64B <= MinSize <= 1GB && MinSize < NormalSize")
+var ErrMaxSize = errors.New("MaxSize is required and must be 64B <= MaxSize <= 1GB && MaxSize > NormalSize")
+var ErrWindow = errors.New("Window must be 0 <= Window < MaxSize/2")
+
+// MAXP is hash-less: it cuts at a position whose value is strictly
+// greater than the values of every position within Window bytes on either
+// side of it. The value of a position is the 8 bytes starting there, read
+// as a big-endian integer, as single bytes would tie far too often for
+// any useful window. On random data the first local maximum confirmed
+// past MinSize lies about 3/2 of Window further, so the default radius
+// is derived from the expected distance between MinSize and NormalSize.
+type MAXP struct {
+	// monotonic deque of the positions whose value may still be the
+	// maximum of a window, with decreasing values, and whether an equal
+	// value precedes them within Window bytes
+	positions []int
+	values    []uint64
+	ties      []bool
+}
+
+// Options tunes maxp when passed as ChunkerOpts.Extension.
+type Options struct {
+	// Window is the radius of the region a cutpoint must hold the maximum
+	// of, zero deriving it from NormalSize - MinSize.
+	Window int
+}
+
+// extension returns a copy of the Options in opts to modify, and sets it
+// as the Extension of opts.
+func extension(opts *chunkers.ChunkerOpts) *Options {
+	ext := &Options{}
+	if current, ok := opts.Extension.(*Options); ok {
+		*ext = *current
+	}
+	opts.Extension = ext
+	return ext
+}
+
+// WithWindow sets the Window of the Extension.
+func WithWindow(window int) chunkers.Option {
+	return func(opts *chunkers.ChunkerOpts) { extension(opts).Window = window }
+}
+
+// configuredWindow returns the window set for options, the one of the
+// Extension taking precedence, or zero.
+func configuredWindow(options *chunkers.ChunkerOpts) int {
+	if ext, ok := options.Extension.(*Options); ok && ext.Window != 0 {
+		return ext.Window
+	}
+	return options.Window
+}
+
+func newMAXP() chunkers.ChunkerImplementation {
+	return &MAXP{}
+}
+
+func (c *MAXP) DefaultOptions() *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{
+		MinSize:    2 * 1024,
+		MaxSize:    64 * 1024,
+		NormalSize: 8 * 1024,
+	}
+}
+
+func (c *MAXP) Validate(options *chunkers.ChunkerOpts) error {
+	if options.NormalSize < 64 || options.NormalSize > 1024*1024*1024 {
+		return ErrNormalSize
+	}
+	if options.MinSize < 64 || options.MinSize > 1024*1024*1024 || options.MinSize >= options.NormalSize {
+		return ErrMinSize
+	}
+	if options.MaxSize < 64 || options.MaxSize > 1024*1024*1024 || options.MaxSize <= options.NormalSize {
+		return ErrMaxSize
+	}
+	if window := configuredWindow(options); window < 0 || window >= options.MaxSize/2 {
+		return ErrWindow
+	}
+	return nil
+}
+
+// radius returns the window radius in effect for options
+func radius(options *chunkers.ChunkerOpts) int {
+	if window := configuredWindow(options); window != 0 {
+		return window
+	}
+	return max(1, (options.NormalSize-options.MinSize)*2/3)
+}
+
+// ResolveOptions sets the Window in effect in the Extension.
+func (c *MAXP) ResolveOptions(options *chunkers.ChunkerOpts) {
+	extension(options).Window = radius(options)
+	options.Window = 0
+}
+
+func (c *MAXP) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	MinSize := options.MinSize
+	MaxSize := options.MaxSize
+
+	switch {
+	case n <= MinSize:
+		return n
+	case n >= MaxSize:
+		n = MaxSize
+	}
+
+	w := radius(options)
+	size := 2*w + 1
+	if len(c.positions) != size {
+		c.positions = make([]int, size)
+		c.values = make([]uint64, size)
+		c.ties = make([]bool, size)
+	}
+
+	// the deque is a ring of 2*Window+1 entries, the span of the window
+	// centered on i-w, which is confirmed or not once i is read
+	head, length := 0, 0
+
+	start := max(MinSize, w)
+	for i := start - w; i+8 <= n; i++ {
+		value := binary.BigEndian.Uint64(data[i:])
+
+		if length > 0 && c.positions[head] < i-2*w {
+			head = (head + 1) % size
+			length--
+		}
+		tie := false
+		for length > 0 {
+			back := (head + length - 1) % size
+			if c.values[back] > value {
+				break
+			}
+			if c.values[back] == value && c.positions[back] >= i-w {
+				tie = true
+			}
+			length--
+		}
+		c.positions[(head+length)%size] = i
+		c.values[(head+length)%size] = value
+		c.ties[(head+length)%size] = tie
+		length++
+
+		if center := i - w; center >= start && c.positions[head] == center && !c.ties[head] {
+			return center
+		}
+	}
+	return n
+}
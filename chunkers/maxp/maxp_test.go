@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package maxp
+
+import (
+	"encoding/binary"
+	mathrand2 "math/rand/v2"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func average(t *testing.T, opts *chunkers.ChunkerOpts, data []byte) int {
+	c := newMAXP()
+	if err := c.Validate(opts); err != nil {
+		t.Fatalf(`%v: %s`, opts, err)
+	}
+	w := radius(opts)
+
+	chunks := 0
+	for remaining := data; len(remaining) > 0; chunks++ {
+		cutpoint := c.Algorithm(opts, remaining, min(len(remaining), opts.MaxSize))
+		if cutpoint > opts.MaxSize || (cutpoint < opts.MinSize && cutpoint != len(remaining)) {
+			t.Fatalf(`cutpoint %d out of bounds`, cutpoint)
+		}
+		if cutpoint+w+8 <= len(remaining) && cutpoint < opts.MaxSize {
+			value := binary.BigEndian.Uint64(remaining[cutpoint:])
+			for j := cutpoint - w; j <= cutpoint+w; j++ {
+				if j != cutpoint && binary.BigEndian.Uint64(remaining[j:]) >= value {
+					t.Fatalf(`cutpoint %d is not a local maximum over radius %d`, cutpoint, w)
+				}
+			}
+		}
+		remaining = remaining[cutpoint:]
+	}
+	return len(data) / chunks
+}
+
+func Test_Cutpoints(t *testing.T) {
+	var seed [32]byte
+	data := make([]byte, 16<<20)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	opts := newMAXP().DefaultOptions()
+	if avg := average(t, opts, data); avg < opts.NormalSize*3/4 || avg > opts.NormalSize*5/4 {
+		t.Fatalf(`average chunk size %d too far from %d`, avg, opts.NormalSize)
+	}
+
+	// the radius tunes the average
+	small := average(t, &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, Window: 256}, data)
+	large := average(t, &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, Window: 8192}, data)
+	if small >= opts.NormalSize || large <= opts.NormalSize {
+		t.Fatalf(`expected radius 256 to average below and 8192 above %d, got %d and %d`, opts.NormalSize, small, large)
+	}
+
+	if err := newMAXP().Validate(&chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, Window: 32 << 10}); err != ErrWindow {
+		t.Fatalf(`expected ErrWindow, got %v`, err)
+	}
+}
+
+// reference is the quadratic definition of the MAXP cutpoint: the first
+// position past MinSize strictly greater than every position within the
+// radius on either side.
+func reference(opts *chunkers.ChunkerOpts, data []byte, n int) int {
+	w := radius(opts)
+	for center := max(opts.MinSize, w); center+w+8 <= n; center++ {
+		value := binary.BigEndian.Uint64(data[center:])
+		maximum := true
+		for j := center - w; j <= center+w && maximum; j++ {
+			maximum = j == center || binary.BigEndian.Uint64(data[j:]) < value
+		}
+		if maximum {
+			return center
+		}
+	}
+	return n
+}
+
+func Test_Reference(t *testing.T) {
+	var seed [32]byte
+	rng := mathrand2.NewChaCha8(seed)
+	data := make([]byte, 1<<20)
+	rng.Read(data)
+
+	// few distinct bytes make ties and failed candidates frequent
+	lowEntropy := make([]byte, len(data))
+	for i := range lowEntropy {
+		lowEntropy[i] = data[i] & 0x3
+	}
+
+	c := newMAXP()
+	for _, opts := range []*chunkers.ChunkerOpts{
+		c.DefaultOptions(),
+		{MinSize: 64, NormalSize: 128, MaxSize: 4096, Window: 3},
+		{MinSize: 64, NormalSize: 128, MaxSize: 4096, Window: 1},
+	} {
+		for _, input := range [][]byte{data, lowEntropy} {
+			for remaining := input; len(remaining) > 0; {
+				n := min(len(remaining), opts.MaxSize)
+				expected := n
+				if n > opts.MinSize {
+					expected = reference(opts, remaining, n)
+				}
+				if cutpoint := c.Algorithm(opts, remaining, n); cutpoint != expected {
+					t.Fatalf(`%v: expected cutpoint %d, got %d`, opts, expected, cutpoint)
+				}
+				remaining = remaining[expected:]
+			}
+		}
+	}
+}
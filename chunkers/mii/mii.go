@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package mii
+
+import (
+	"errors"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func init() {
+	chunkers.MustRegister("mii", newMII)
+}
+
+var ErrNormalSize = errors.New("NormalSize is required and must be 64B <= NormalSize <= 1GB")
+var ErrMinSize = errors.New("MinSize is required and must be 64B <= MinSize <= 1GB && MinSize < NormalSize")
+var ErrMaxSize = errors.New("MaxSize is required and must be 64B <= MaxSize <= 1GB && MaxSize > NormalSize")
+
+// MII (Minimal Incremental Interval) is hash-less: it cuts once the input
+// has been strictly increasing for a number of consecutive bytes, the
+// interval length. On random data a position ends such a run with a
+// probability of roughly 1/(length+1)!, so the length is derived from the
+// expected distance between MinSize and NormalSize, rounding down.
+type MII struct {
+}
+
+func newMII() chunkers.ChunkerImplementation {
+	return &MII{}
+}
+
+func (c *MII) DefaultOptions() *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{
+		MinSize:    2 * 1024,
+		MaxSize:    64 * 1024,
+		NormalSize: 8 * 1024,
+	}
+}
+
+func (c *MII) Validate(options *chunkers.ChunkerOpts) error {
+	if options.NormalSize == 0 || options.NormalSize < 64 || options.NormalSize > 1024*1024*1024 {
+		return ErrNormalSize
+	}
+	if options.MinSize < 64 || options.MinSize > 1024*1024*1024 || options.MinSize >= options.NormalSize {
+		return ErrMinSize
+	}
+	if options.MaxSize < 64 || options.MaxSize > 1024*1024*1024 || options.MaxSize <= options.NormalSize {
+		return ErrMaxSize
+	}
+	return nil
+}
+
+// intervalLength returns the longest interval whose expected spacing on
+// random data, (length+1)!, does not exceed distance.
+func intervalLength(distance int) int {
+	length := 1
+	for factorial := 2 * 3; factorial <= distance; factorial *= length + 2 {
+		length++
+	}
+	return length
+}
+
+func (c *MII) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	MinSize := options.MinSize
+	MaxSize := options.MaxSize
+
+	switch {
+	case n <= MinSize:
+		return n
+	case n >= MaxSize:
+		n = MaxSize
+	}
+
+	length := intervalLength(options.NormalSize - MinSize)
+
+	increments := 0
+	prev := data[MinSize-1]
+	for i := MinSize; i < n; i++ {
+		curr := data[i]
+		if curr > prev {
+			increments++
+			if increments == length {
+				return i
+			}
+		} else {
+			increments = 0
+		}
+		prev = curr
+	}
+	return n
+}
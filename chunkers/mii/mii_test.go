@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package mii
+
+import (
+	mathrand2 "math/rand/v2"
+	"testing"
+)
+
+func Test_intervalLength(t *testing.T) {
+	for distance, expected := range map[int]int{2: 1, 5: 1, 6: 2, 24: 3, 5039: 5, 5040: 6, 6144: 6, 40320: 7} {
+		if length := intervalLength(distance); length != expected {
+			t.Fatalf(`distance %d: expected length %d, got %d`, distance, expected, length)
+		}
+	}
+}
+
+func Test_Cutpoints(t *testing.T) {
+	var seed [32]byte
+	data := make([]byte, 16<<20)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	c := newMII().(*MII)
+	opts := c.DefaultOptions()
+
+	chunks := 0
+	for remaining := data; len(remaining) > 0; chunks++ {
+		cutpoint := c.Algorithm(opts, remaining, min(len(remaining), opts.MaxSize))
+		if cutpoint > opts.MaxSize || (cutpoint < opts.MinSize && cutpoint != len(remaining)) {
+			t.Fatalf(`cutpoint %d out of bounds`, cutpoint)
+		}
+		if cutpoint < len(remaining) && cutpoint < opts.MaxSize {
+			run := remaining[cutpoint-intervalLength(opts.NormalSize-opts.MinSize) : cutpoint+1]
+			for i := 1; i < len(run); i++ {
+				if run[i] <= run[i-1] {
+					t.Fatalf(`cutpoint %d does not end an increasing interval`, cutpoint)
+				}
+			}
+		}
+		remaining = remaining[cutpoint:]
+	}
+
+	average := len(data) / chunks
+	if average < opts.NormalSize/2 || average > opts.NormalSize*2 {
+		t.Fatalf(`average chunk size %d too far from %d`, average, opts.NormalSize)
+	}
+
+	// no increasing run at all in low-entropy data: only forced cuts
+	zeroes := make([]byte, 1<<20)
+	if cutpoint := c.Algorithm(opts, zeroes, len(zeroes)); cutpoint != opts.MaxSize {
+		t.Fatalf(`expected a forced cut at MaxSize, got %d`, cutpoint)
+	}
+}
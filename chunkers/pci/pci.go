@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package pci
+
+import (
+	"errors"
+	"math/bits"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func init() {
+	chunkers.MustRegister("pci", newPCI)
+}
+
+var ErrNormalSize = errors.New("NormalSize is required and must be 64B <= NormalSize <= 1GB")
+var ErrMinSize = errors.New("MinSize is required and must be 64B <= MinSize <= 1GB && MinSize < NormalSize")
+var ErrMaxSize = errors.New("MaxSize is required and must be 64B <= MaxSize <= 1GB && MaxSize > NormalSize")
+
+const windowSize = 16
+
+// PCI (Parity Check of Interval) counts the one-bits in a sliding window
+// of 16 bytes and cuts once the count reaches a threshold. The threshold
+// is the smallest count whose probability on random data, a binomial
+// tail over the 128 bits of the window, is at most the inverse of the
+// expected distance between MinSize and NormalSize. Consecutive windows
+// share all but one byte so counts above the threshold come in clusters,
+// and the threshold is rounded up to a whole count: together they make
+// cuts about four times rarer than the tail suggests, so the distance is
+// scaled down accordingly.
+type PCI struct {
+	distance  int
+	threshold int
+}
+
+func newPCI() chunkers.ChunkerImplementation {
+	return &PCI{}
+}
+
+func (c *PCI) DefaultOptions() *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{
+		MinSize:    2 * 1024,
+		MaxSize:    64 * 1024,
+		NormalSize: 8 * 1024,
+	}
+}
+
+func (c *PCI) Validate(options *chunkers.ChunkerOpts) error {
+	if options.NormalSize < 64 || options.NormalSize > 1024*1024*1024 {
+		return ErrNormalSize
+	}
+	if options.MinSize < 64 || options.MinSize > 1024*1024*1024 || options.MinSize >= options.NormalSize {
+		return ErrMinSize
+	}
+	if options.MaxSize < 64 || options.MaxSize > 1024*1024*1024 || options.MaxSize <= options.NormalSize {
+		return ErrMaxSize
+	}
+	return nil
+}
+
+// threshold returns the smallest one-bits count reached by a random
+// window with a probability of at most 1/distance.
+func threshold(distance int) int {
+	const n = 8 * windowSize
+
+	// p[k] = C(n, k) / 2^n, summed from the top
+	p := 1.
+	for i := 0; i < n; i++ {
+		p /= 2
+	}
+	tail := p
+	for k := n; k > 0; k-- {
+		p = p * float64(k) / float64(n-k+1)
+		if tail+p > 1/float64(distance) {
+			return k
+		}
+		tail += p
+	}
+	return 1
+}
+
+func (c *PCI) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	MinSize := options.MinSize
+	MaxSize := options.MaxSize
+
+	switch {
+	case n <= MinSize:
+		return n
+	case n >= MaxSize:
+		n = MaxSize
+	}
+
+	if distance := options.NormalSize - MinSize; c.distance != distance {
+		c.distance = distance
+		c.threshold = threshold(max(1, distance/4))
+	}
+
+	ones := 0
+	for _, b := range data[MinSize-windowSize : MinSize] {
+		ones += bits.OnesCount8(b)
+	}
+	for i := MinSize; i < n; i++ {
+		ones += bits.OnesCount8(data[i]) - bits.OnesCount8(data[i-windowSize])
+		if ones >= c.threshold {
+			return i + 1
+		}
+	}
+	return n
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package pci
+
+import (
+	"math/bits"
+	mathrand2 "math/rand/v2"
+	"testing"
+)
+
+func Test_Threshold(t *testing.T) {
+	// P(X >= 64) > 1/2 for X ~ B(128, 1/2), P(X >= 65) < 1/2
+	if k := threshold(2); k != 65 {
+		t.Fatalf(`expected threshold 65 for distance 2, got %d`, k)
+	}
+	previous := 0
+	for distance := 2; distance < 1<<30; distance *= 2 {
+		k := threshold(distance)
+		if k < previous || k > 8*windowSize {
+			t.Fatalf(`threshold %d for distance %d out of order`, k, distance)
+		}
+		previous = k
+	}
+}
+
+func Test_Cutpoints(t *testing.T) {
+	var seed [32]byte
+	data := make([]byte, 16<<20)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	c := newPCI().(*PCI)
+	opts := c.DefaultOptions()
+
+	chunks := 0
+	for remaining := data; len(remaining) > 0; chunks++ {
+		cutpoint := c.Algorithm(opts, remaining, min(len(remaining), opts.MaxSize))
+		if cutpoint > opts.MaxSize || (cutpoint < opts.MinSize && cutpoint != len(remaining)) {
+			t.Fatalf(`cutpoint %d out of bounds`, cutpoint)
+		}
+		if cutpoint < len(remaining) && cutpoint < opts.MaxSize {
+			ones := 0
+			for _, b := range remaining[cutpoint-windowSize : cutpoint] {
+				ones += bits.OnesCount8(b)
+			}
+			if ones < c.threshold {
+				t.Fatalf(`cutpoint %d: window holds %d one-bits, below %d`, cutpoint, ones, c.threshold)
+			}
+		}
+		remaining = remaining[cutpoint:]
+	}
+
+	average := len(data) / chunks
+	if average < opts.NormalSize/2 || average > opts.NormalSize*2 {
+		t.Fatalf(`average chunk size %d too far from %d`, average, opts.NormalSize)
+	}
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package quickcdc
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+)
+
+func init() {
+	chunkers.MustRegister("quickcdc", newQuickCDC)
+}
+
+var ErrNormalSize = errors.New("NormalSize is required and must be a power of two, 64B <= NormalSize <= 1GB")
+var ErrMinSize = errors.New("MinSize is required and must be 64B <= MinSize <= 1GB && MinSize < NormalSize")
+var ErrMaxSize = errors.New("MaxSize is required and must be 64B <= MaxSize <= 1GB && MaxSize > NormalSize")
+
+const (
+	// chunks are recognized by their first frontSize bytes, confirmed by
+	// their last 8 bytes
+	frontSize = 3
+
+	jumpTableBits = 14
+)
+
+type jump struct {
+	front  uint32
+	end    uint64
+	length int
+}
+
+// QuickCDC remembers the front and end features of the chunks it emitted
+// in a jump table. A chunk starting with a known front feature is assumed
+// to be a duplicate: the chunker jumps straight to the recorded length and
+// cuts there if the end feature matches, without rolling a hash over the
+// chunk. Otherwise it falls back to Gear hashing with normalized masks.
+//
+// The table is bounded, newer chunks evict older ones sharing a slot, so
+// jumps only help with duplicates that are recent enough.
+type QuickCDC struct {
+	jumps [1 << jumpTableBits]jump
+	keyed fastcdc.KeyedTable
+}
+
+func newQuickCDC() chunkers.ChunkerImplementation {
+	return &QuickCDC{}
+}
+
+func (c *QuickCDC) DefaultOptions() *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{
+		MinSize:    2 * 1024,
+		MaxSize:    64 * 1024,
+		NormalSize: 8 * 1024,
+	}
+}
+
+func (c *QuickCDC) Validate(options *chunkers.ChunkerOpts) error {
+	if options.NormalSize < 64 || options.NormalSize > 1024*1024*1024 || options.NormalSize&(options.NormalSize-1) != 0 {
+		return ErrNormalSize
+	}
+	if options.MinSize < 64 || options.MinSize > 1024*1024*1024 || options.MinSize >= options.NormalSize {
+		return ErrMinSize
+	}
+	if options.MaxSize < 64 || options.MaxSize > 1024*1024*1024 || options.MaxSize <= options.NormalSize {
+		return ErrMaxSize
+	}
+	return nil
+}
+
+// front returns the front feature of data, with a marker bit so that an
+// empty slot never matches.
+func front(data []byte) (uint32, int) {
+	feature := uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16 | 1<<24
+	return feature, int((feature * 0x9e3779b1) >> (32 - jumpTableBits))
+}
+
+func (c *QuickCDC) Emit(options *chunkers.ChunkerOpts, chunk []byte) {
+	// a short tail was not cut by content
+	if len(chunk) < options.MinSize {
+		return
+	}
+	feature, slot := front(chunk)
+	c.jumps[slot] = jump{
+		front:  feature,
+		end:    binary.LittleEndian.Uint64(chunk[len(chunk)-8:]),
+		length: len(chunk),
+	}
+}
+
+// MarshalBinary encodes the jump table for Chunker.State.
+func (c *QuickCDC) MarshalBinary() ([]byte, error) {
+	var state []byte
+	for slot, j := range c.jumps {
+		if j.length == 0 {
+			continue
+		}
+		state = binary.AppendUvarint(state, uint64(slot))
+		state = binary.LittleEndian.AppendUint32(state, j.front)
+		state = binary.LittleEndian.AppendUint64(state, j.end)
+		state = binary.AppendUvarint(state, uint64(j.length))
+	}
+	return state, nil
+}
+
+// UnmarshalBinary restores the jump table encoded by MarshalBinary.
+func (c *QuickCDC) UnmarshalBinary(state []byte) error {
+	c.jumps = [1 << jumpTableBits]jump{}
+	for len(state) != 0 {
+		slot, n := binary.Uvarint(state)
+		if n <= 0 || slot >= uint64(len(c.jumps)) || len(state)-n < 12 {
+			return chunkers.ErrState
+		}
+		state = state[n:]
+		j := jump{
+			front: binary.LittleEndian.Uint32(state),
+			end:   binary.LittleEndian.Uint64(state[4:]),
+		}
+		state = state[12:]
+		length, n := binary.Uvarint(state)
+		if n <= 0 || length == 0 || length > math.MaxInt32 {
+			return chunkers.ErrState
+		}
+		state = state[n:]
+		j.length = int(length)
+		c.jumps[slot] = j
+	}
+	return nil
+}
+
+// Entropy reports the Gear table shared with fastcdc.
+func (c *QuickCDC) Entropy(options *chunkers.ChunkerOpts) []chunkers.EntropyInput {
+	return []chunkers.EntropyInput{chunkers.NewEntropyInput("gear table", c.keyed.Table(options.Key))}
+}
+
+// Keyed marks the Gear table as derived from ChunkerOpts.Key.
+func (c *QuickCDC) Keyed() {}
+
+func (c *QuickCDC) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	MinSize := options.MinSize
+	MaxSize := options.MaxSize
+	NormalSize := options.NormalSize
+
+	switch {
+	case n <= MinSize:
+		return n
+	case n >= MaxSize:
+		n = MaxSize
+	case n <= NormalSize:
+		NormalSize = n
+	}
+
+	feature, slot := front(data)
+	if j := c.jumps[slot]; j.front == feature && j.length <= n &&
+		binary.LittleEndian.Uint64(data[j.length-8:j.length]) == j.end {
+		return j.length
+	}
+
+	// one more bit than the normal size before it, one less after
+	maskBits := bits.Len(uint(options.NormalSize)) - 1
+	maskS := ^uint64(0) << (64 - (maskBits + 1))
+	maskL := ^uint64(0) << (64 - (maskBits - 1))
+
+	g := c.keyed.Table(options.Key)
+	fp := uint64(0)
+	i := MinSize
+	for ; i < NormalSize; i++ {
+		fp = (fp << 1) + g[data[i]]
+		if fp&maskS == 0 {
+			return i
+		}
+	}
+	for ; i < n; i++ {
+		fp = (fp << 1) + g[data[i]]
+		if fp&maskL == 0 {
+			return i
+		}
+	}
+	return n
+}
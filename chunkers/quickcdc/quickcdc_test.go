@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package quickcdc
+
+import (
+	"bytes"
+	mathrand2 "math/rand/v2"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_Jump(t *testing.T) {
+	var seed [32]byte
+	data := make([]byte, 1<<20)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	c := newQuickCDC().(*QuickCDC)
+	opts := c.DefaultOptions()
+
+	// a chunk cut by hashing, recorded
+	length := c.Algorithm(opts, data, opts.MaxSize)
+	c.Emit(opts, data[:length])
+
+	// same front and end features: jump regardless of the bytes between
+	duplicate := append([]byte(nil), data[:opts.MaxSize]...)
+	duplicate[length/2] ^= 0xff
+	for i := length; i < len(duplicate); i++ {
+		duplicate[i] = 0
+	}
+	if cutpoint := c.Algorithm(opts, duplicate, opts.MaxSize); cutpoint != length {
+		t.Fatalf(`expected a jump to %d, got %d`, length, cutpoint)
+	}
+
+	// a different end feature falls back to hashing
+	duplicate[length-1] ^= 0xff
+	fallback := newQuickCDC().Algorithm(opts, duplicate, opts.MaxSize)
+	if cutpoint := c.Algorithm(opts, duplicate, opts.MaxSize); cutpoint != fallback {
+		t.Fatalf(`expected the hashed cutpoint %d, got %d`, fallback, cutpoint)
+	}
+}
+
+func Test_Cutpoints(t *testing.T) {
+	var seed [32]byte
+	half := make([]byte, 8<<20)
+	mathrand2.NewChaCha8(seed).Read(half)
+	data := append(append([]byte(nil), half...), half...)
+
+	chunker, err := chunkers.NewChunker("quickcdc", bytes.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	opts := newQuickCDC().DefaultOptions()
+
+	seen := make(map[string]bool)
+	chunks, duplicates := 0, 0
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		if int(length) > opts.MaxSize || (int(length) < opts.MinSize && int(offset+length) != len(data)) {
+			t.Fatalf(`chunk length %d out of bounds`, length)
+		}
+		if seen[string(chunk)] {
+			duplicates++
+		}
+		seen[string(chunk)] = true
+		chunks++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	average := len(data) / chunks
+	if average < opts.NormalSize/2 || average > opts.NormalSize*2 {
+		t.Fatalf(`average chunk size %d too far from %d`, average, opts.NormalSize)
+	}
+	// all but the chunks around the seam repeat
+	if duplicates < chunks/2-4 {
+		t.Fatalf(`expected about %d duplicate chunks, got %d`, chunks/2, duplicates)
+	}
+}
+
+func Test_State(t *testing.T) {
+	var seed [32]byte
+	data := make([]byte, 4<<20)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	c := newQuickCDC().(*QuickCDC)
+	opts := c.DefaultOptions()
+	for offset := 0; offset < len(data); {
+		remaining := data[offset:]
+		cutpoint := c.Algorithm(opts, remaining, min(len(remaining), opts.MaxSize))
+		c.Emit(opts, remaining[:cutpoint])
+		offset += cutpoint
+	}
+	state, err := c.MarshalBinary()
+	if err != nil {
+		t.Fatalf(`state error: %s`, err)
+	}
+
+	restored := newQuickCDC().(*QuickCDC)
+	if err := restored.UnmarshalBinary(state); err != nil {
+		t.Fatalf(`state error: %s`, err)
+	}
+	if restored.jumps != c.jumps {
+		t.Fatalf(`restored jump table differs`)
+	}
+	if err := restored.UnmarshalBinary(state[:len(state)-1]); err != chunkers.ErrState {
+		t.Fatalf(`truncated state: got %v, expected %v`, err, chunkers.ErrState)
+	}
+}
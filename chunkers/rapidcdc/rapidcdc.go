@@ -0,0 +1,252 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package rapidcdc
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+)
+
+func init() {
+	chunkers.MustRegister("rapidcdc", newRapidCDC)
+}
+
+var ErrNormalSize = errors.New("NormalSize is required and must be a power of two, 64B <= NormalSize <= 1GB")
+var ErrMinSize = errors.New("MinSize is required and must be 64B <= MinSize <= 1GB && MinSize < NormalSize")
+var ErrMaxSize = errors.New("MaxSize is required and must be 64B <= MaxSize <= 1GB && MaxSize > NormalSize")
+
+const (
+	historyBits = 14
+	historySize = 4
+)
+
+// successors lists the sizes of the chunks that followed a chunk, most
+// recent first
+type successors struct {
+	key   uint64
+	sizes [historySize]int
+}
+
+// RapidCDC exploits duplicate locality: a chunk seen before tends to be
+// followed by the same chunk as last time. The sizes of the chunks that
+// followed recent chunks are remembered, and after emitting a known chunk
+// the chunker first tries these sizes, accepting one if the position
+// passes the same boundary test as hashing would apply. On redundant
+// streams most bytes are then never hashed. Otherwise it falls back to
+// Gear hashing with normalized masks.
+//
+// Chunks are recognized by their size and their first and last 8 bytes
+// rather than a cryptographic digest, which would cost what is saved. The
+// history is a bounded table where newer chunks evict older ones sharing
+// a slot, so only recent enough duplicates are predicted.
+type RapidCDC struct {
+	history [1 << historyBits]successors
+	last    uint64
+	hits    int
+	keyed   fastcdc.KeyedTable
+}
+
+func newRapidCDC() chunkers.ChunkerImplementation {
+	return &RapidCDC{}
+}
+
+func (c *RapidCDC) DefaultOptions() *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{
+		MinSize:    2 * 1024,
+		MaxSize:    64 * 1024,
+		NormalSize: 8 * 1024,
+	}
+}
+
+func (c *RapidCDC) Validate(options *chunkers.ChunkerOpts) error {
+	if options.NormalSize < 64 || options.NormalSize > 1024*1024*1024 || options.NormalSize&(options.NormalSize-1) != 0 {
+		return ErrNormalSize
+	}
+	if options.MinSize < 64 || options.MinSize > 1024*1024*1024 || options.MinSize >= options.NormalSize {
+		return ErrMinSize
+	}
+	if options.MaxSize < 64 || options.MaxSize > 1024*1024*1024 || options.MaxSize <= options.NormalSize {
+		return ErrMaxSize
+	}
+	return nil
+}
+
+func fingerprint(chunk []byte) uint64 {
+	first := binary.LittleEndian.Uint64(chunk)
+	last := binary.LittleEndian.Uint64(chunk[len(chunk)-8:])
+	key := (first ^ bits.RotateLeft64(last, 29) ^ uint64(len(chunk))) * 0x9e3779b97f4a7c15
+	return key | 1
+}
+
+func slot(key uint64) int {
+	return int(key >> (64 - historyBits))
+}
+
+func (c *RapidCDC) Emit(options *chunkers.ChunkerOpts, chunk []byte) {
+	// a short tail was not cut by content
+	if len(chunk) < options.MinSize {
+		c.last = 0
+		return
+	}
+
+	if c.last != 0 {
+		entry := &c.history[slot(c.last)]
+		if entry.key != c.last {
+			*entry = successors{key: c.last}
+		}
+		i := 0
+		for i < historySize-1 && entry.sizes[i] != len(chunk) {
+			i++
+		}
+		copy(entry.sizes[1:i+1], entry.sizes[:i])
+		entry.sizes[0] = len(chunk)
+	}
+	c.last = fingerprint(chunk)
+}
+
+// MarshalBinary encodes the history for Chunker.State.
+func (c *RapidCDC) MarshalBinary() ([]byte, error) {
+	state := binary.LittleEndian.AppendUint64(nil, c.last)
+	state = binary.AppendUvarint(state, uint64(c.hits))
+	for slot, entry := range c.history {
+		if entry.key == 0 {
+			continue
+		}
+		state = binary.AppendUvarint(state, uint64(slot))
+		state = binary.LittleEndian.AppendUint64(state, entry.key)
+		for _, size := range entry.sizes {
+			state = binary.AppendUvarint(state, uint64(size))
+		}
+	}
+	return state, nil
+}
+
+// UnmarshalBinary restores the history encoded by MarshalBinary.
+func (c *RapidCDC) UnmarshalBinary(state []byte) error {
+	c.history = [1 << historyBits]successors{}
+	if len(state) < 8 {
+		return chunkers.ErrState
+	}
+	c.last = binary.LittleEndian.Uint64(state)
+	hits, n := binary.Uvarint(state[8:])
+	if n <= 0 || hits > math.MaxInt {
+		return chunkers.ErrState
+	}
+	c.hits = int(hits)
+	state = state[8+n:]
+	for len(state) != 0 {
+		slot, n := binary.Uvarint(state)
+		if n <= 0 || slot >= uint64(len(c.history)) || len(state)-n < 8 {
+			return chunkers.ErrState
+		}
+		state = state[n:]
+		entry := successors{key: binary.LittleEndian.Uint64(state)}
+		state = state[8:]
+		for i := range entry.sizes {
+			size, n := binary.Uvarint(state)
+			if n <= 0 || size > math.MaxInt32 {
+				return chunkers.ErrState
+			}
+			state = state[n:]
+			entry.sizes[i] = int(size)
+		}
+		c.history[slot] = entry
+	}
+	return nil
+}
+
+func masks(options *chunkers.ChunkerOpts) (uint64, uint64) {
+	// one more bit than the normal size before it, one less after
+	maskBits := bits.Len(uint(options.NormalSize)) - 1
+	return ^uint64(0) << (64 - (maskBits + 1)), ^uint64(0) << (64 - (maskBits - 1))
+}
+
+// boundary reports whether hashing would accept a cut before data[i]
+func boundary(options *chunkers.ChunkerOpts, g *[256]uint64, data []byte, i int) bool {
+	maskS, maskL := masks(options)
+	mask := maskL
+	if i < options.NormalSize {
+		mask = maskS
+	}
+
+	fp := uint64(0)
+	for _, b := range data[max(options.MinSize, i-63) : i+1] {
+		fp = (fp << 1) + g[b]
+	}
+	return fp&mask == 0
+}
+
+// Entropy reports the Gear table shared with fastcdc.
+func (c *RapidCDC) Entropy(options *chunkers.ChunkerOpts) []chunkers.EntropyInput {
+	return []chunkers.EntropyInput{chunkers.NewEntropyInput("gear table", c.keyed.Table(options.Key))}
+}
+
+// Keyed marks the Gear table as derived from ChunkerOpts.Key.
+func (c *RapidCDC) Keyed() {}
+
+func (c *RapidCDC) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	MinSize := options.MinSize
+	MaxSize := options.MaxSize
+	NormalSize := options.NormalSize
+
+	switch {
+	case n <= MinSize:
+		return n
+	case n >= MaxSize:
+		n = MaxSize
+	case n <= NormalSize:
+		NormalSize = n
+	}
+
+	g := c.keyed.Table(options.Key)
+	if c.last != 0 {
+		if entry := &c.history[slot(c.last)]; entry.key == c.last {
+			for _, size := range entry.sizes {
+				if size == 0 {
+					break
+				}
+				if (size < n && boundary(options, g, data, size)) || (size == n && n == MaxSize) {
+					c.hits++
+					return size
+				}
+			}
+		}
+	}
+
+	maskS, maskL := masks(options)
+
+	fp := uint64(0)
+	i := MinSize
+	for ; i < NormalSize; i++ {
+		fp = (fp << 1) + g[data[i]]
+		if fp&maskS == 0 {
+			return i
+		}
+	}
+	for ; i < n; i++ {
+		fp = (fp << 1) + g[data[i]]
+		if fp&maskL == 0 {
+			return i
+		}
+	}
+	return n
+}
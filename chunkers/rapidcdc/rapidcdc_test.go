@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package rapidcdc
+
+import (
+	mathrand2 "math/rand/v2"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+)
+
+// split drives the implementation the way a Chunker does
+func split(c *RapidCDC, data []byte) []int {
+	opts := c.DefaultOptions()
+
+	var lengths []int
+	for offset := 0; offset < len(data); {
+		remaining := data[offset:]
+		cutpoint := c.Algorithm(opts, remaining, min(len(remaining), opts.MaxSize))
+		c.Emit(opts, remaining[:cutpoint])
+		lengths = append(lengths, cutpoint)
+		offset += cutpoint
+	}
+	return lengths
+}
+
+func Test_Boundary(t *testing.T) {
+	var seed [32]byte
+	data := make([]byte, 1<<20)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	c := newRapidCDC().(*RapidCDC)
+	opts := c.DefaultOptions()
+
+	// every hashed cut passes the verification applied to predictions
+	for offset := 0; len(data)-offset > opts.MaxSize; {
+		cutpoint := c.Algorithm(opts, data[offset:], opts.MaxSize)
+		if cutpoint < opts.MaxSize && !boundary(opts, &fastcdc.G, data[offset:], cutpoint) {
+			t.Fatalf(`hashed cutpoint %d fails the boundary check`, cutpoint)
+		}
+		offset += cutpoint
+	}
+}
+
+func Test_Prediction(t *testing.T) {
+	var seed [32]byte
+	half := make([]byte, 8<<20)
+	mathrand2.NewChaCha8(seed).Read(half)
+	data := append(append([]byte(nil), half...), half...)
+
+	hashed := split(newRapidCDC().(*RapidCDC), half)
+
+	c := newRapidCDC().(*RapidCDC)
+	lengths := split(c, data)
+	// slot collisions in the history cost a few predictions
+	if c.hits < len(hashed)*9/10 {
+		t.Fatalf(`expected the second half to be predicted, %d hits for %d chunks`, c.hits, len(hashed))
+	}
+
+	// the first half is chunked as it would be on its own, and so is the
+	// second half once the seam is passed
+	for i := 0; i < len(hashed)-1; i++ {
+		if lengths[i] != hashed[i] {
+			t.Fatalf(`chunk %d: expected length %d, got %d`, i, hashed[i], lengths[i])
+		}
+	}
+	tail := lengths[len(lengths)-len(hashed)+4 : len(lengths)-1]
+	for i, length := range tail {
+		if expected := hashed[len(hashed)-len(tail)-1+i]; length != expected {
+			t.Fatalf(`predicted chunk %d: expected length %d, got %d`, i, expected, length)
+		}
+	}
+
+	average := len(half) / len(hashed)
+	opts := c.DefaultOptions()
+	if average < opts.NormalSize/2 || average > opts.NormalSize*2 {
+		t.Fatalf(`average chunk size %d too far from %d`, average, opts.NormalSize)
+	}
+}
+
+func Test_State(t *testing.T) {
+	var seed [32]byte
+	half := make([]byte, 4<<20)
+	mathrand2.NewChaCha8(seed).Read(half)
+
+	c := newRapidCDC().(*RapidCDC)
+	split(c, half)
+	// with predictions
+	split(c, half[:1<<20])
+	state, err := c.MarshalBinary()
+	if err != nil {
+		t.Fatalf(`state error: %s`, err)
+	}
+
+	restored := newRapidCDC().(*RapidCDC)
+	if err := restored.UnmarshalBinary(state); err != nil {
+		t.Fatalf(`state error: %s`, err)
+	}
+	if restored.history != c.history || restored.last != c.last || restored.hits != c.hits {
+		t.Fatalf(`restored history differs`)
+	}
+	if err := restored.UnmarshalBinary(state[:len(state)-1]); err != chunkers.ErrState {
+		t.Fatalf(`truncated state: got %v, expected %v`, err, chunkers.ErrState)
+	}
+}
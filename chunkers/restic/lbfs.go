@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package restic
+
+import (
+	"errors"
+	"math/bits"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func init() {
+	chunkers.MustRegister("lbfs", newLBFS)
+}
+
+var ErrLBFSNormalSize = errors.New("NormalSize is required and must be a power of two, 256B <= NormalSize <= 1GB")
+var ErrLBFSPolynomial = errors.New("Polynomial must be irreducible and of degree 8 < degree <= 63")
+
+const (
+	// LBFSPolynomial is FINGERPRINT_PT of the LBFS sources, used when
+	// Options.Polynomial is zero.
+	LBFSPolynomial = 0xbfe6b8a5bf378d83
+
+	lbfsWindowSize = 48   // rabinpoly's window size
+	lbfsBreakmark  = 0x78 // BREAKMARK_VALUE
+)
+
+// LBFS is the chunker of the Low-Bandwidth Network File System, which
+// introduced content-defined chunking: it cuts where the low bits of the
+// Rabin fingerprint over a 48 bytes window equal 0x78, with chunks of
+// 2KiB to 64KiB and 13 bits matched for 8KiB on average. It rolls the
+// fingerprint with the same tables as restic, the fingerprint restarting
+// from zero at every chunk.
+type LBFS struct {
+	polynomial uint64
+	tables     *tables
+	keyed      keyedPolynomial
+}
+
+func newLBFS() chunkers.ChunkerImplementation {
+	return &LBFS{}
+}
+
+func (c *LBFS) DefaultOptions() *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{
+		MinSize:    2 * 1024,
+		MaxSize:    64 * 1024,
+		NormalSize: 8 * 1024,
+	}
+}
+
+func (c *LBFS) Validate(options *chunkers.ChunkerOpts) error {
+	if options.NormalSize < 256 || options.NormalSize > 1024*1024*1024 || options.NormalSize&(options.NormalSize-1) != 0 {
+		return ErrLBFSNormalSize
+	}
+	if options.MinSize < 64 || options.MinSize > 1024*1024*1024 || options.MinSize >= options.NormalSize {
+		return ErrMinSize
+	}
+	if options.MaxSize < 64 || options.MaxSize > 1024*1024*1024 || options.MaxSize <= options.NormalSize {
+		return ErrMaxSize
+	}
+	if p := pol(polynomial(options)); p != 0 && (p.deg() <= 8 || !p.irreducible()) {
+		return ErrLBFSPolynomial
+	}
+	return nil
+}
+
+// polynomialFor returns the polynomial in effect for options, as restic
+// does with LBFSPolynomial as the default.
+func (c *LBFS) polynomialFor(options *chunkers.ChunkerOpts) uint64 {
+	p := polynomial(options)
+	switch {
+	case p != 0:
+		return p
+	case len(options.Key) != 0:
+		return c.keyed.derive(options.Key, "lbfs polynomial")
+	}
+	return LBFSPolynomial
+}
+
+// Entropy reports the polynomial in use, LBFSPolynomial unless
+// Options.Polynomial or ChunkerOpts.Key is set.
+func (c *LBFS) Entropy(options *chunkers.ChunkerOpts) []chunkers.EntropyInput {
+	return []chunkers.EntropyInput{chunkers.NewEntropyInput("polynomial", c.polynomialFor(options))}
+}
+
+// Keyed marks the polynomial as derived from ChunkerOpts.Key.
+func (c *LBFS) Keyed() {}
+
+func (c *LBFS) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	MinSize := options.MinSize
+	MaxSize := options.MaxSize
+
+	switch {
+	case n <= MinSize:
+		return n
+	case n >= MaxSize:
+		n = MaxSize
+	}
+
+	polynomial := c.polynomialFor(options)
+	if c.tables == nil || c.polynomial != polynomial {
+		c.polynomial = polynomial
+		c.tables = tablesFor(polynomial, lbfsWindowSize)
+	}
+	tab := c.tables
+	polShift := uint(bits.Len64(polynomial)-1) - 8
+	mask := uint64(options.NormalSize - 1)
+
+	// the window starts zeroed, which zero bytes leave unchanged, so
+	// rolling can start at the last window before MinSize
+	var window [lbfsWindowSize]byte
+	wpos := 0
+	digest := uint64(0)
+
+	for i := max(0, MinSize-lbfsWindowSize); i < n; i++ {
+		b := data[i]
+		digest ^= tab.out[window[wpos]]
+		window[wpos] = b
+		wpos++
+		if wpos == lbfsWindowSize {
+			wpos = 0
+		}
+
+		index := digest >> polShift
+		digest = (digest<<8 | uint64(b)) ^ tab.mod[index]
+
+		if digest&mask == lbfsBreakmark && i+1 >= MinSize {
+			return i + 1
+		}
+	}
+	return n
+}
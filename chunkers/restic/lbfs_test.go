@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package restic
+
+import (
+	"math/bits"
+	mathrand2 "math/rand/v2"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+// fingerprint computes the Rabin fingerprint of data from scratch as
+// rabinpoly defines it: the bits of data, most significant first, read as
+// a polynomial over GF(2) and reduced modulo polynomial by long division.
+// It shares no code with the rolling tables it is a reference for.
+func fingerprint(data []byte, polynomial uint64) uint64 {
+	degree := bits.Len64(polynomial) - 1
+	h := uint64(0)
+	for _, b := range data {
+		for i := 7; i >= 0; i-- {
+			carry := h >> (degree - 1) & 1
+			h = h<<1 | uint64(b>>i&1)
+			if carry != 0 {
+				h ^= polynomial
+			}
+		}
+	}
+	return h
+}
+
+func Test_LBFS_Fingerprint(t *testing.T) {
+	for _, test := range []struct {
+		data     []byte
+		expected uint64
+	}{
+		// polynomials of a lower degree than FINGERPRINT_PT are their own
+		// remainder
+		{[]byte{lbfsBreakmark}, lbfsBreakmark},
+		{[]byte{0x40, 0, 0, 0, 0, 0, 0, 0x78}, 1<<62 | 0x78},
+		// X^63 is FINGERPRINT_PT minus its leading term
+		{[]byte{0x80, 0, 0, 0, 0, 0, 0, 0}, LBFSPolynomial ^ 1<<63},
+		// which is of degree 61, so X^64 is X times it and X^65 is reduced
+		// once more
+		{[]byte{1, 0, 0, 0, 0, 0, 0, 0, 0}, (LBFSPolynomial ^ 1<<63) << 1},
+		{[]byte{2, 0, 0, 0, 0, 0, 0, 0, 0}, (LBFSPolynomial^1<<63)<<2 ^ LBFSPolynomial},
+	} {
+		if h := fingerprint(test.data, LBFSPolynomial); h != test.expected {
+			t.Fatalf(`fingerprint of %x: expected %#x, got %#x`, test.data, test.expected, h)
+		}
+	}
+}
+
+// Cutpoints are those rabinpoly's parameters select, fingerprints being
+// computed from scratch over every window.
+func Test_LBFS_Matches_Reference(t *testing.T) {
+	var seed [32]byte
+	data := make([]byte, 512<<10)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	c := newLBFS()
+	for _, opts := range []*chunkers.ChunkerOpts{
+		c.DefaultOptions(),
+		{MinSize: 64, NormalSize: 256, MaxSize: 4096, Polynomial: DefaultPolynomial},
+	} {
+		if err := c.Validate(opts); err != nil {
+			t.Fatalf(`options rejected: %s`, err)
+		}
+		polynomial := opts.Polynomial
+		if polynomial == 0 {
+			polynomial = LBFSPolynomial
+		}
+
+		chunks := 0
+		for remaining := data; len(remaining) > 0; chunks++ {
+			n := min(len(remaining), opts.MaxSize)
+			expected := n
+			if n > opts.MinSize {
+				for i := opts.MinSize - 1; i < n; i++ {
+					window := remaining[max(0, i+1-lbfsWindowSize) : i+1]
+					if fingerprint(window, polynomial)%uint64(opts.NormalSize) == lbfsBreakmark {
+						expected = i + 1
+						break
+					}
+				}
+			}
+			if cutpoint := c.Algorithm(opts, remaining, n); cutpoint != expected {
+				t.Fatalf(`chunk %d: expected cutpoint %d, got %d`, chunks, expected, cutpoint)
+			}
+			remaining = remaining[expected:]
+		}
+		if average := len(data) / chunks; average < opts.NormalSize/2 || average > opts.NormalSize*2 {
+			t.Fatalf(`unexpected average chunk size %d`, average)
+		}
+	}
+}
+
+func Test_LBFS_Validate(t *testing.T) {
+	c := newLBFS()
+
+	opts := c.DefaultOptions()
+	if err := c.Validate(opts); err != nil {
+		t.Fatalf(`default options rejected: %s`, err)
+	}
+
+	opts.Polynomial = LBFSPolynomial & (1<<62 - 1) * 2
+	if err := c.Validate(opts); err != ErrLBFSPolynomial {
+		t.Fatalf(`expected ErrLBFSPolynomial, got %v`, err)
+	}
+
+	opts = c.DefaultOptions()
+	opts.NormalSize = 128
+	opts.MinSize = 64
+	if err := c.Validate(opts); err != ErrLBFSNormalSize {
+		t.Fatalf(`expected ErrLBFSNormalSize, got %v`, err)
+	}
+}
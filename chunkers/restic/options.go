@@ -0,0 +1,49 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package restic
+
+import (
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+// Options tunes restic and lbfs when passed as ChunkerOpts.Extension.
+type Options struct {
+	// Polynomial is the irreducible polynomial of the Rabin fingerprint,
+	// zero selecting one derived from ChunkerOpts.Key if set, else the
+	// default of the algorithm.
+	Polynomial uint64
+}
+
+// WithPolynomial sets the Polynomial of the Extension.
+func WithPolynomial(polynomial uint64) chunkers.Option {
+	return func(opts *chunkers.ChunkerOpts) {
+		ext := &Options{}
+		if current, ok := opts.Extension.(*Options); ok {
+			*ext = *current
+		}
+		ext.Polynomial = polynomial
+		opts.Extension = ext
+	}
+}
+
+// polynomial returns the polynomial set for options, the one of the
+// Extension taking precedence, or zero.
+func polynomial(options *chunkers.ChunkerOpts) uint64 {
+	if ext, ok := options.Extension.(*Options); ok && ext.Polynomial != 0 {
+		return ext.Polynomial
+	}
+	return options.Polynomial
+}
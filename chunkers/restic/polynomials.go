@@ -0,0 +1,159 @@
+/*
+ * Ported from github.com/restic/chunker:
+ *
+ * Copyright (c) 2014, Alexander Neumann <alexander@bumpern.de>
+ * All rights reserved.
+ *
+ * Redistribution and use in source and binary forms, with or without
+ * modification, are permitted provided that the following conditions are met:
+ *
+ * 1. Redistributions of source code must retain the above copyright notice, this
+ *    list of conditions and the following disclaimer.
+ *
+ * 2. Redistributions in binary form must reproduce the above copyright notice,
+ *    this list of conditions and the following disclaimer in the documentation
+ *    and/or other materials provided with the distribution.
+ *
+ * THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+ * ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+ * WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+ * DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+ * FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+ * DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+ * SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+ * CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+ * OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+ * OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+ */
+
+package restic
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/bits"
+)
+
+// pol is a polynomial from F_2[X].
+type pol uint64
+
+func (x pol) add(y pol) pol {
+	return x ^ y
+}
+
+// deg returns the degree of x, -1 if x is zero.
+func (x pol) deg() int {
+	return bits.Len64(uint64(x)) - 1
+}
+
+func (x pol) divMod(d pol) (pol, pol) {
+	if x == 0 {
+		return 0, 0
+	}
+	if d == 0 {
+		panic("division by zero")
+	}
+
+	D := d.deg()
+	diff := x.deg() - D
+	if diff < 0 {
+		return 0, x
+	}
+
+	var q pol
+	for diff >= 0 {
+		q |= 1 << uint(diff)
+		x = x.add(d << uint(diff))
+		diff = x.deg() - D
+	}
+	return q, x
+}
+
+func (x pol) mod(d pol) pol {
+	_, r := x.divMod(d)
+	return r
+}
+
+func (x pol) gcd(f pol) pol {
+	if f == 0 {
+		return x
+	}
+	if x == 0 {
+		return f
+	}
+	if x.deg() < f.deg() {
+		x, f = f, x
+	}
+	return f.gcd(x.mod(f))
+}
+
+// irreducible reports whether x is irreducible over F_2, using Ben-Or's
+// reducibility test.
+func (x pol) irreducible() bool {
+	for i := 1; i <= x.deg()/2; i++ {
+		if x.gcd(qp(uint(i), x)) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// mulMod computes x*f mod g.
+func (x pol) mulMod(f, g pol) pol {
+	if x == 0 || f == 0 {
+		return 0
+	}
+
+	var res pol
+	for i := 0; i <= f.deg(); i++ {
+		if f&(1<<uint(i)) != 0 {
+			a := x
+			for j := 0; j < i; j++ {
+				a = (a << 1).mod(g)
+			}
+			res = res.add(a).mod(g)
+		}
+	}
+	return res
+}
+
+// qp computes (x^(2^p)-x) mod g.
+func qp(p uint, g pol) pol {
+	num := 1 << p
+
+	res := pol(2)
+	for i := 1; i < num; i *= 2 {
+		res = res.mulMod(res, g)
+	}
+	return res.add(2).mod(g)
+}
+
+// RandomPolynomial returns a random irreducible polynomial of degree 53,
+// the kind restic generates once per repository, suitable for
+// Options.Polynomial.
+func RandomPolynomial() (uint64, error) {
+	return DerivePolynomial(rand.Reader)
+}
+
+// DerivePolynomial returns the first irreducible polynomial of degree 53
+// drawn from source, so that a seeded source always yields the same
+// polynomial and a repository's chunking can be reproduced from its seed.
+func DerivePolynomial(source io.Reader) (uint64, error) {
+	for i := 0; i < 1e6; i++ {
+		var f pol
+		if err := binary.Read(source, binary.LittleEndian, &f); err != nil {
+			return 0, err
+		}
+
+		// degree 53 and not trivially reducible
+		f &= (1 << 54) - 1
+		f |= (1 << 53) | 1
+
+		if f.irreducible() {
+			return uint64(f), nil
+		}
+	}
+	return 0, errors.New("unable to find new random irreducible polynomial")
+}
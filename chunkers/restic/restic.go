@@ -0,0 +1,231 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package restic
+
+import (
+	"bytes"
+	"errors"
+	"math/bits"
+	mathrand2 "math/rand/v2"
+	"sync"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func init() {
+	chunkers.MustRegister("restic", newRestic)
+}
+
+var ErrNormalSize = errors.New("NormalSize is required and must be a power of two, 64B <= NormalSize <= 1GB")
+var ErrMinSize = errors.New("MinSize is required and must be 64B <= MinSize <= 1GB && MinSize < NormalSize")
+var ErrMaxSize = errors.New("MaxSize is required and must be 64B <= MaxSize <= 1GB && MaxSize > NormalSize")
+var ErrPolynomial = errors.New("Polynomial must be irreducible and of degree 8 < degree <= 53")
+
+// DefaultPolynomial is used when Options.Polynomial is zero. Restic
+// repositories each carry their own, which must be passed through the
+// options to produce the same chunks.
+const DefaultPolynomial = 0x3DA3358B4DC173
+
+const windowSize = 64
+
+type tables struct {
+	out [256]uint64
+	mod [256]uint64
+}
+
+type tablesKey struct {
+	polynomial uint64
+	window     int
+}
+
+// tables are read-only once computed, share them across chunkers
+var cache struct {
+	sync.Mutex
+	entries map[tablesKey]*tables
+}
+
+// mulXMod returns h * X^n mod p for h of a lower degree than p, shifting
+// one bit at a time so that polynomials of degree 63 never overflow.
+func mulXMod(h, p uint64, n int) uint64 {
+	carry := uint64(1) << (bits.Len64(p) - 2)
+	for ; n > 0; n-- {
+		if h&carry != 0 {
+			h = h<<1 ^ p
+		} else {
+			h <<= 1
+		}
+	}
+	return h
+}
+
+// tablesFor returns the tables rolling the Rabin fingerprint modulo
+// polynomial over window bytes.
+func tablesFor(polynomial uint64, window int) *tables {
+	cache.Lock()
+	defer cache.Unlock()
+	key := tablesKey{polynomial, window}
+	if t, exists := cache.entries[key]; exists {
+		return t
+	}
+
+	t := &tables{}
+
+	// out[b] = Hash(b || 0 || ... || 0) over a full window, adding it
+	// slides b out of the window
+	for b := 0; b < 256; b++ {
+		t.out[b] = mulXMod(uint64(b), polynomial, 8*(window-1))
+	}
+
+	// mod[b] reduces the 8 bits above the degree and cancels them out
+	k := pol(polynomial).deg()
+	for b := 0; b < 256; b++ {
+		t.mod[b] = mulXMod(uint64(b), polynomial, k) | uint64(b)<<uint(k)
+	}
+
+	if cache.entries == nil {
+		cache.entries = make(map[tablesKey]*tables)
+	}
+	cache.entries[key] = t
+	return t
+}
+
+// keyedPolynomial caches the polynomial derived from a ChunkerOpts.Key.
+type keyedPolynomial struct {
+	key        []byte
+	polynomial uint64
+}
+
+// derive returns the polynomial derived from key for label, the way
+// restic draws a repository's own from a random source.
+func (k *keyedPolynomial) derive(key []byte, label string) uint64 {
+	if k.polynomial == 0 || !bytes.Equal(k.key, key) {
+		polynomial, err := DerivePolynomial(mathrand2.NewChaCha8(chunkers.DeriveKey(key, label)))
+		if err != nil {
+			// ChaCha8 never fails to read, and one in about 53 draws is
+			// irreducible
+			panic(err)
+		}
+		k.key = bytes.Clone(key)
+		k.polynomial = polynomial
+	}
+	return k.polynomial
+}
+
+// Restic is the Rabin fingerprint chunker of restic, cutting where the
+// low bits of the fingerprint over a 64 bytes window are all zero. Given
+// a repository's polynomial and boundaries it produces the same chunks as
+// restic, NormalSize being 1 << averageBits.
+type Restic struct {
+	polynomial uint64
+	tables     *tables
+	keyed      keyedPolynomial
+}
+
+func newRestic() chunkers.ChunkerImplementation {
+	return &Restic{}
+}
+
+func (c *Restic) DefaultOptions() *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{
+		MinSize:    512 * 1024,
+		MaxSize:    8 * 1024 * 1024,
+		NormalSize: 1024 * 1024,
+	}
+}
+
+func (c *Restic) Validate(options *chunkers.ChunkerOpts) error {
+	if options.NormalSize < 64 || options.NormalSize > 1024*1024*1024 || options.NormalSize&(options.NormalSize-1) != 0 {
+		return ErrNormalSize
+	}
+	if options.MinSize < 64 || options.MinSize > 1024*1024*1024 || options.MinSize >= options.NormalSize {
+		return ErrMinSize
+	}
+	if options.MaxSize < 64 || options.MaxSize > 1024*1024*1024 || options.MaxSize <= options.NormalSize {
+		return ErrMaxSize
+	}
+	if p := pol(polynomial(options)); p != 0 && (p.deg() <= 8 || p.deg() > 53 || !p.irreducible()) {
+		return ErrPolynomial
+	}
+	return nil
+}
+
+// polynomialFor returns the polynomial in effect for options: Polynomial
+// if set, as it is a secret of its own, else one derived from Key if set,
+// else DefaultPolynomial.
+func (c *Restic) polynomialFor(options *chunkers.ChunkerOpts) uint64 {
+	p := polynomial(options)
+	switch {
+	case p != 0:
+		return p
+	case len(options.Key) != 0:
+		return c.keyed.derive(options.Key, "restic polynomial")
+	}
+	return DefaultPolynomial
+}
+
+// Entropy reports the polynomial in use, DefaultPolynomial unless
+// Options.Polynomial or ChunkerOpts.Key is set.
+func (c *Restic) Entropy(options *chunkers.ChunkerOpts) []chunkers.EntropyInput {
+	return []chunkers.EntropyInput{chunkers.NewEntropyInput("polynomial", c.polynomialFor(options))}
+}
+
+// Keyed marks the polynomial as derived from ChunkerOpts.Key.
+func (c *Restic) Keyed() {}
+
+func (c *Restic) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	MinSize := options.MinSize
+	MaxSize := options.MaxSize
+
+	switch {
+	case n <= MinSize:
+		return n
+	case n >= MaxSize:
+		n = MaxSize
+	}
+
+	polynomial := c.polynomialFor(options)
+	if c.tables == nil || c.polynomial != polynomial {
+		c.polynomial = polynomial
+		c.tables = tablesFor(polynomial, windowSize)
+	}
+	tab := c.tables
+	polShift := uint(bits.Len64(polynomial)-1) - 8
+	mask := uint64(options.NormalSize - 1)
+
+	// restic primes the window with a single 1 byte, then skips to the
+	// last window before MinSize
+	var window [windowSize]byte
+	window[0] = 1
+	wpos := 1
+	digest := uint64(1)
+
+	for i := MinSize - windowSize; i < n; i++ {
+		b := data[i]
+		wpos %= windowSize
+		digest ^= tab.out[window[wpos]]
+		window[wpos] = b
+		wpos++
+
+		index := digest >> polShift
+		digest = (digest<<8 | uint64(b)) ^ tab.mod[index]
+
+		if digest&mask == 0 && i+1 >= MinSize {
+			return i + 1
+		}
+	}
+	return n
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package restic
+
+import (
+	"bytes"
+	mathrand2 "math/rand/v2"
+	"testing"
+)
+
+func Test_Irreducible(t *testing.T) {
+	for polynomial, expected := range map[pol]bool{
+		DefaultPolynomial:    true,
+		0x3DA3358B4DC173 * 2: false,
+		0x11b:                true, // x^8+x^4+x^3+x+1
+		0x11d:                true,
+		0x101:                false, // (x+1)^8
+	} {
+		if polynomial.irreducible() != expected {
+			t.Fatalf(`%#x: expected irreducible to be %v`, uint64(polynomial), expected)
+		}
+	}
+}
+
+func Test_RandomPolynomial(t *testing.T) {
+	polynomial, err := RandomPolynomial()
+	if err != nil {
+		t.Fatalf(`RandomPolynomial error: %s`, err)
+	}
+	if pol(polynomial).deg() != 53 || !pol(polynomial).irreducible() {
+		t.Fatalf(`%#x is not an irreducible polynomial of degree 53`, polynomial)
+	}
+
+	opts := newRestic().DefaultOptions()
+	opts.Polynomial = polynomial
+	if err := newRestic().Validate(opts); err != nil {
+		t.Fatalf(`random polynomial rejected: %s`, err)
+	}
+
+	var seed [32]byte
+	source := make([]byte, 1<<16)
+	mathrand2.NewChaCha8(seed).Read(source)
+	a, errA := DerivePolynomial(bytes.NewReader(source))
+	b, errB := DerivePolynomial(bytes.NewReader(source))
+	if errA != nil || errB != nil || a != b {
+		t.Fatalf(`DerivePolynomial is not deterministic: %#x, %#x`, a, b)
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	c := newRestic()
+
+	opts := c.DefaultOptions()
+	if err := c.Validate(opts); err != nil {
+		t.Fatalf(`default options rejected: %s`, err)
+	}
+
+	opts.Polynomial = DefaultPolynomial * 2
+	if err := c.Validate(opts); err != ErrPolynomial {
+		t.Fatalf(`expected ErrPolynomial, got %v`, err)
+	}
+
+	opts = c.DefaultOptions()
+	opts.NormalSize = 1000 * 1000
+	if err := c.Validate(opts); err != ErrNormalSize {
+		t.Fatalf(`expected ErrNormalSize, got %v`, err)
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package rsync
+
+// CharOffset is added to every byte by librsync's rollsum, so that runs
+// of zeroes still move the checksum.
+const CharOffset = 31
+
+// Rollsum is the Adler-32 derived weak checksum of rsync, as computed by
+// librsync: s1 sums the bytes and s2 sums the successive values of s1,
+// both modulo 2^16, so that a byte can be rotated out of the window in
+// constant time. Signatures built with it match librsync's weak sums.
+type Rollsum struct {
+	count  uint32
+	s1, s2 uint32
+}
+
+// Reset empties the window.
+func (r *Rollsum) Reset() {
+	*r = Rollsum{}
+}
+
+// Update appends p to the window.
+func (r *Rollsum) Update(p []byte) {
+	for _, c := range p {
+		r.Rollin(c)
+	}
+}
+
+// Rollin appends c to the window.
+func (r *Rollsum) Rollin(c byte) {
+	r.s1 += uint32(c) + CharOffset
+	r.s2 += r.s1
+	r.count++
+}
+
+// Rollout removes c, the oldest byte, from the window.
+func (r *Rollsum) Rollout(c byte) {
+	r.s1 -= uint32(c) + CharOffset
+	r.s2 -= r.count * (uint32(c) + CharOffset)
+	r.count--
+}
+
+// Rotate slides the window by one byte, removing out and appending in.
+func (r *Rollsum) Rotate(out, in byte) {
+	r.s1 += uint32(in) - uint32(out)
+	r.s2 += r.s1 - r.count*(uint32(out)+CharOffset)
+}
+
+// Digest returns the weak checksum of the window, s2 in the high 16 bits
+// and s1 in the low ones.
+func (r *Rollsum) Digest() uint32 {
+	return r.s2<<16 | r.s1&0xffff
+}
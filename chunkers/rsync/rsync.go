@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package rsync
+
+import (
+	"errors"
+	"math"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func init() {
+	chunkers.MustRegister("rsync", newRsync)
+}
+
+var ErrNormalSize = errors.New("NormalSize is required and must be a power of two with 64B <= NormalSize <= 1GB, and at most 64KiB with the default Window")
+var ErrMinSize = errors.New("MinSize must be 0 <= MinSize < NormalSize")
+var ErrMaxSize = errors.New("MaxSize is required and must be MaxSize <= 1GB && MaxSize > NormalSize")
+var ErrWindow = errors.New("Window must be 0 <= Window <= MaxSize")
+
+// DefaultWindow is the number of bytes the checksum covers when Window is
+// zero.
+const DefaultWindow = 64
+
+// Rsync cuts where the low bits of the rsync rolling checksum, the s2 sum
+// of a Rollsum over the last Window bytes, are all ones. NormalSize sets
+// how many bits must match, so cuts happen about NormalSize bytes past
+// MinSize. It is related to bupsplit, which also tests the low bits of
+// s2 over a 64 bytes window, but bup seeds s1 and s2 as if the window was
+// already full of charOffset bytes, so the two do not cut at the same
+// positions. Exposing Rollsum lets signature-based sync tools share the
+// checksum with the chunker.
+type Rsync struct {
+}
+
+// Options tunes rsync when passed as ChunkerOpts.Extension.
+type Options struct {
+	// Window is the number of bytes the checksum covers, zero selecting
+	// DefaultWindow.
+	Window int
+}
+
+// extension returns a copy of the Options in opts to modify, and sets it
+// as the Extension of opts.
+func extension(opts *chunkers.ChunkerOpts) *Options {
+	ext := &Options{}
+	if current, ok := opts.Extension.(*Options); ok {
+		*ext = *current
+	}
+	opts.Extension = ext
+	return ext
+}
+
+// WithWindow sets the Window of the Extension.
+func WithWindow(window int) chunkers.Option {
+	return func(opts *chunkers.ChunkerOpts) { extension(opts).Window = window }
+}
+
+// configuredWindow returns the window set for options, the one of the
+// Extension taking precedence, or zero.
+func configuredWindow(options *chunkers.ChunkerOpts) int {
+	if ext, ok := options.Extension.(*Options); ok && ext.Window != 0 {
+		return ext.Window
+	}
+	return options.Window
+}
+
+func newRsync() chunkers.ChunkerImplementation {
+	return &Rsync{}
+}
+
+func (c *Rsync) DefaultOptions() *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{
+		MinSize:    2 * 1024,
+		NormalSize: 8 * 1024,
+		MaxSize:    64 * 1024,
+	}
+}
+
+func (c *Rsync) Validate(options *chunkers.ChunkerOpts) error {
+	if options.NormalSize < 64 || options.NormalSize > 1024*1024*1024 ||
+		options.NormalSize&(options.NormalSize-1) != 0 {
+		return ErrNormalSize
+	}
+	if options.MinSize < 0 || options.MinSize >= options.NormalSize {
+		return ErrMinSize
+	}
+	if options.MaxSize > 1024*1024*1024 || options.MaxSize <= options.NormalSize {
+		return ErrMaxSize
+	}
+	if window := configuredWindow(options); window < 0 || window > options.MaxSize {
+		return ErrWindow
+	}
+	if options.NormalSize > maxNormalSize(window(options)) {
+		return ErrNormalSize
+	}
+	return nil
+}
+
+// maxNormalSize returns the largest NormalSize the checksum of window
+// bytes cuts at: s2 sums the bytes weighted by their position in the
+// window, and is about normally distributed, so its low bits stop being
+// uniform past three standard deviations and it would cut larger chunks
+// at MaxSize. That is 64KiB for the default window.
+func maxNormalSize(window int) int {
+	w := float64(window)
+	variance := (256*256 - 1) / 12.0 * w * (w + 1) * (2*w + 1) / 6
+	return int(min(3*math.Sqrt(variance), 1024*1024*1024))
+}
+
+// window returns the window size in effect for options
+func window(options *chunkers.ChunkerOpts) int {
+	if window := configuredWindow(options); window != 0 {
+		return window
+	}
+	return DefaultWindow
+}
+
+// ResolveOptions sets the Window in effect in the Extension.
+func (c *Rsync) ResolveOptions(options *chunkers.ChunkerOpts) {
+	extension(options).Window = window(options)
+	options.Window = 0
+}
+
+func (c *Rsync) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	MinSize := options.MinSize
+	MaxSize := options.MaxSize
+
+	switch {
+	case n <= MinSize:
+		return n
+	case n >= MaxSize:
+		n = MaxSize
+	}
+
+	window := window(options)
+	mask := uint32(options.NormalSize - 1)
+
+	// the checksum only depends on the last window bytes, so rolling can
+	// start that far before the first acceptable cutpoint
+	start := max(0, MinSize-window)
+
+	var sum Rollsum
+	for i := start; i < n; i++ {
+		if i-start < window {
+			sum.Rollin(data[i])
+		} else {
+			sum.Rotate(data[i-window], data[i])
+		}
+		if sum.s2&mask == mask && i+1 >= MinSize {
+			return i + 1
+		}
+	}
+	return n
+}
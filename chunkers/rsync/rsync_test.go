@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package rsync
+
+import (
+	mathrand2 "math/rand/v2"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+// weakSum computes the librsync weak checksum of block from its definition
+func weakSum(block []byte) uint32 {
+	var s1, s2 uint32
+	for i, c := range block {
+		s1 += uint32(c) + CharOffset
+		s2 += uint32(len(block)-i) * (uint32(c) + CharOffset)
+	}
+	return (s2&0xffff)<<16 | s1&0xffff
+}
+
+func Test_Rollsum(t *testing.T) {
+	var sum Rollsum
+	sum.Update([]byte("a"))
+	if sum.Digest() != 0x00800080 {
+		t.Fatalf(`unexpected digest %#x for "a"`, sum.Digest())
+	}
+
+	var seed [32]byte
+	data := make([]byte, 1<<16)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	const window = 700
+	sum.Reset()
+	sum.Update(data[:window])
+	for i := window; i < len(data); i++ {
+		sum.Rotate(data[i-window], data[i])
+		if expected := weakSum(data[i-window+1 : i+1]); sum.Digest() != expected {
+			t.Fatalf(`rolled digest %#x differs from %#x at %d`, sum.Digest(), expected, i)
+		}
+	}
+
+	sum.Rollout(data[len(data)-window])
+	if expected := weakSum(data[len(data)-window+1:]); sum.Digest() != expected {
+		t.Fatalf(`digest %#x after rolling out differs from %#x`, sum.Digest(), expected)
+	}
+}
+
+func Test_Cutpoints(t *testing.T) {
+	var seed [32]byte
+	data := make([]byte, 16<<20)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	c := newRsync()
+	for _, opts := range []*chunkers.ChunkerOpts{
+		c.DefaultOptions(),
+		{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, Window: 4 << 10},
+	} {
+		if err := c.Validate(opts); err != nil {
+			t.Fatalf(`%v: %s`, opts, err)
+		}
+		chunks := 0
+		for remaining := data; len(remaining) > 0; chunks++ {
+			cutpoint := c.Algorithm(opts, remaining, min(len(remaining), opts.MaxSize))
+			if cutpoint > opts.MaxSize || (cutpoint < opts.MinSize && cutpoint != len(remaining)) {
+				t.Fatalf(`cutpoint %d out of bounds`, cutpoint)
+			}
+			remaining = remaining[cutpoint:]
+		}
+		expected := opts.MinSize + opts.NormalSize
+		if avg := len(data) / chunks; avg < expected*3/4 || avg > expected*5/4 {
+			t.Fatalf(`%v: average chunk size %d too far from %d`, opts, avg, expected)
+		}
+	}
+}
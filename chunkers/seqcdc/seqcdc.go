@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package seqcdc
+
+import (
+	"errors"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func init() {
+	chunkers.MustRegister("seqcdc", newSeqCDC)
+}
+
+var ErrNormalSize = errors.New("NormalSize is required and must be 256B <= NormalSize <= 1GB")
+var ErrMinSize = errors.New("MinSize is required and must be 64B <= MinSize <= 1GB && MinSize < NormalSize")
+var ErrMaxSize = errors.New("MaxSize is required and must be 64B <= MaxSize <= 1GB && MaxSize > NormalSize")
+
+const (
+	// a boundary is seqLength consecutive decreasing bytes
+	seqLength = 5
+
+	// after skipTrigger bytes breaking the sequence, the region is deemed
+	// unlikely to hold a boundary and 1/skipRatio of the expected distance
+	// to a boundary is skipped, which brings the average close to
+	// NormalSize on random data
+	skipTrigger = 50
+	skipRatio   = 10
+
+	// below smallNormalSize, runs of seqLength bytes are too far apart
+	// on their own to reach NormalSize, shorter runs are used instead
+	// with longer skips
+	smallNormalSize = 4 * 1024
+	smallSeqLength  = 4
+	smallSkipRatio  = 2
+)
+
+// SeqCDC is hash-less: it cuts at the end of a run of strictly decreasing
+// bytes and, to go faster than scanning every byte, skips ahead whenever
+// the bytes keep breaking the sequence, as such regions seldom hold one.
+type SeqCDC struct {
+}
+
+func newSeqCDC() chunkers.ChunkerImplementation {
+	return &SeqCDC{}
+}
+
+func (c *SeqCDC) DefaultOptions() *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{
+		MinSize:    2 * 1024,
+		MaxSize:    64 * 1024,
+		NormalSize: 8 * 1024,
+	}
+}
+
+func (c *SeqCDC) Validate(options *chunkers.ChunkerOpts) error {
+	if options.NormalSize < 256 || options.NormalSize > 1024*1024*1024 {
+		return ErrNormalSize
+	}
+	if options.MinSize < 64 || options.MinSize > 1024*1024*1024 || options.MinSize >= options.NormalSize {
+		return ErrMinSize
+	}
+	if options.MaxSize < 64 || options.MaxSize > 1024*1024*1024 || options.MaxSize <= options.NormalSize {
+		return ErrMaxSize
+	}
+	return nil
+}
+
+func (c *SeqCDC) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	MinSize := options.MinSize
+	MaxSize := options.MaxSize
+
+	switch {
+	case n <= MinSize:
+		return n
+	case n >= MaxSize:
+		n = MaxSize
+	}
+
+	length, skipSize := seqLength, (options.NormalSize-MinSize)/skipRatio
+	if options.NormalSize < smallNormalSize {
+		length, skipSize = smallSeqLength, (options.NormalSize-MinSize)/smallSkipRatio
+	}
+
+	sequence, opposing := 0, 0
+	for i := MinSize; i < n; i++ {
+		if data[i] < data[i-1] {
+			sequence++
+			if sequence == length {
+				return i
+			}
+			continue
+		}
+
+		sequence = 0
+		opposing++
+		if opposing == skipTrigger {
+			opposing = 0
+			i += skipSize
+		}
+	}
+	return n
+}
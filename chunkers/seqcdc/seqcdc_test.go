@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package seqcdc
+
+import (
+	"bytes"
+	mathrand2 "math/rand/v2"
+	"testing"
+)
+
+func Test_Cutpoints(t *testing.T) {
+	var seed [32]byte
+	data := make([]byte, 16<<20)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	c := newSeqCDC()
+	opts := c.DefaultOptions()
+
+	chunks := 0
+	for remaining := data; len(remaining) > 0; chunks++ {
+		cutpoint := c.Algorithm(opts, remaining, min(len(remaining), opts.MaxSize))
+		if cutpoint > opts.MaxSize || (cutpoint < opts.MinSize && cutpoint != len(remaining)) {
+			t.Fatalf(`cutpoint %d out of bounds`, cutpoint)
+		}
+		if cutpoint < len(remaining) && cutpoint < opts.MaxSize {
+			run := remaining[cutpoint-seqLength : cutpoint+1]
+			for i := 1; i < len(run); i++ {
+				if run[i] >= run[i-1] {
+					t.Fatalf(`cutpoint %d does not end a decreasing sequence`, cutpoint)
+				}
+			}
+		}
+		remaining = remaining[cutpoint:]
+	}
+
+	average := len(data) / chunks
+	if average < opts.NormalSize*3/4 || average > opts.NormalSize*5/4 {
+		t.Fatalf(`average chunk size %d too far from %d`, average, opts.NormalSize)
+	}
+
+	// constant data never decreases: only forced cuts
+	constant := bytes.Repeat([]byte{0x42}, 1<<20)
+	if cutpoint := c.Algorithm(opts, constant, len(constant)); cutpoint != opts.MaxSize {
+		t.Fatalf(`expected a forced cut at MaxSize, got %d`, cutpoint)
+	}
+}
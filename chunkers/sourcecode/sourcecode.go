@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package sourcecode
+
+import (
+	"bytes"
+	"errors"
+	"math/bits"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+)
+
+func init() {
+	chunkers.MustRegister("sourcecode", newSourceCode)
+}
+
+var ErrNormalSize = errors.New("NormalSize is required and must be a power of two, 64B <= NormalSize <= 1GB")
+var ErrMinSize = errors.New("MinSize is required and must be 64B <= MinSize <= 1GB && MinSize < NormalSize")
+var ErrMaxSize = errors.New("MaxSize is required and must be 64B <= MaxSize <= 1GB && MaxSize > NormalSize")
+
+// lineWindow bounds how far a cut may move to reach the end of a line
+const lineWindow = 256
+
+// SourceCode is tuned for source code repositories, made of many small
+// text files where edits insert or remove whole lines. Chunks are small
+// and cuts are moved to the end of the line they fall in, so that chunks
+// hold whole lines. Normalization is asymmetric, one extra mask bit before
+// NormalSize but two fewer after it: Gear hashing over text otherwise
+// yields chunks well above NormalSize and a long tail that dedups poorly.
+// Binary content without newlines is chunked as with Gear.
+type SourceCode struct {
+	keyed fastcdc.KeyedTable
+}
+
+func newSourceCode() chunkers.ChunkerImplementation {
+	return &SourceCode{}
+}
+
+func (c *SourceCode) DefaultOptions() *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{
+		MinSize:    1024,
+		MaxSize:    64 * 1024,
+		NormalSize: 8 * 1024,
+	}
+}
+
+func (c *SourceCode) Validate(options *chunkers.ChunkerOpts) error {
+	if options.NormalSize < 64 || options.NormalSize > 1024*1024*1024 || options.NormalSize&(options.NormalSize-1) != 0 {
+		return ErrNormalSize
+	}
+	if options.MinSize < 64 || options.MinSize > 1024*1024*1024 || options.MinSize >= options.NormalSize {
+		return ErrMinSize
+	}
+	if options.MaxSize < 64 || options.MaxSize > 1024*1024*1024 || options.MaxSize <= options.NormalSize {
+		return ErrMaxSize
+	}
+	return nil
+}
+
+// Entropy reports the Gear table shared with fastcdc.
+func (c *SourceCode) Entropy(options *chunkers.ChunkerOpts) []chunkers.EntropyInput {
+	return []chunkers.EntropyInput{chunkers.NewEntropyInput("gear table", c.keyed.Table(options.Key))}
+}
+
+// Keyed marks the Gear table as derived from ChunkerOpts.Key.
+func (c *SourceCode) Keyed() {}
+
+func (c *SourceCode) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	MinSize := options.MinSize
+	MaxSize := options.MaxSize
+	NormalSize := options.NormalSize
+
+	switch {
+	case n <= MinSize:
+		return n
+	case n >= MaxSize:
+		n = MaxSize
+	case n <= NormalSize:
+		NormalSize = n
+	}
+
+	maskBits := bits.Len(uint(options.NormalSize)) - 1
+	maskS := ^uint64(0) << (64 - (maskBits + 1))
+	maskL := ^uint64(0) << (64 - (maskBits - 2))
+
+	g := c.keyed.Table(options.Key)
+	fp := uint64(0)
+	i := MinSize
+	for ; i < NormalSize; i++ {
+		fp = (fp << 1) + g[data[i]]
+		if fp&maskS == 0 {
+			return endOfLine(data[:n], i)
+		}
+	}
+	for ; i < n; i++ {
+		fp = (fp << 1) + g[data[i]]
+		if fp&maskL == 0 {
+			return endOfLine(data[:n], i)
+		}
+	}
+
+	// forced cut, back off to the last complete line
+	if n == MaxSize {
+		start := max(MinSize, n-lineWindow)
+		if j := bytes.LastIndexByte(data[start:n], '\n'); j >= 0 {
+			return start + j + 1
+		}
+	}
+	return n
+}
+
+// endOfLine moves a cut before data[i] past the next newline, if any is
+// within reach.
+func endOfLine(data []byte, i int) int {
+	window := data[i-1 : min(len(data), i-1+lineWindow)]
+	if j := bytes.IndexByte(window, '\n'); j >= 0 {
+		return i + j
+	}
+	return i
+}
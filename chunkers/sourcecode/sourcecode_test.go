@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package sourcecode
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_EndOfLine(t *testing.T) {
+	c := newSourceCode()
+	opts := c.DefaultOptions()
+
+	// no newline: the cut stays where hashing put it, forced at MaxSize
+	data := bytes.Repeat([]byte{'x'}, 2*opts.MaxSize)
+	if cutpoint := c.Algorithm(opts, data, len(data)); cutpoint != opts.MaxSize {
+		t.Fatalf(`expected a forced cut at %d, got %d`, opts.MaxSize, cutpoint)
+	}
+
+	// a forced cut backs off to the last complete line
+	data[opts.MaxSize-100] = '\n'
+	if cutpoint := c.Algorithm(opts, data, len(data)); cutpoint != opts.MaxSize-99 {
+		t.Fatalf(`expected a cut after the last line at %d, got %d`, opts.MaxSize-99, cutpoint)
+	}
+
+	// cuts move past the end of the line they fall in
+	if cutpoint := endOfLine([]byte("abc\ndef\nghi"), 5); cutpoint != 8 {
+		t.Fatalf(`expected the cut to move to 8, got %d`, cutpoint)
+	}
+	if cutpoint := endOfLine([]byte("abc\ndef\nghi"), 4); cutpoint != 4 {
+		t.Fatalf(`expected a cut already at a line end to stay at 4, got %d`, cutpoint)
+	}
+	if cutpoint := endOfLine([]byte("abc\ndefghi"), 5); cutpoint != 5 {
+		t.Fatalf(`expected the cut to stay at 5 without a newline, got %d`, cutpoint)
+	}
+}
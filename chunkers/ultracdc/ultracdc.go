@@ -18,28 +18,109 @@ package ultracdc
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"math/bits"
+	mathrand2 "math/rand/v2"
 
 	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
 )
 
 func init() {
-	chunkers.Register("ultracdc", newUltraCDC)
+	chunkers.MustRegister("ultracdc", newUltraCDC)
+	chunkers.MustRegister("ultracdc@v2", newUltraCDCv2)
 }
 
 var ErrNormalSize = errors.New("NormalSize is required and must be 64B <= NormalSize <= 1GB")
 var ErrMinSize = errors.New("MinSize is required and must be 64B <= MinSize <= 1GB && MinSize < NormalSize")
 var ErrMaxSize = errors.New("MaxSize is required and must be 64B <= MaxSize <= 1GB && MaxSize > NormalSize")
+var ErrOptions = errors.New("Extension must be *ultracdc.Options with LowEntropyThreshold >= 1 and 1 <= Stride <= 8")
+
+// Options tunes UltraCDC when passed as ChunkerOpts.Extension, NewOptions
+// returning the values of the paper used otherwise.
+type Options struct {
+	// LowEntropyThreshold is the number of consecutive windows equal to
+	// the previous one after which a low-entropy cut happens, LEST in the
+	// paper.
+	LowEntropyThreshold int
+
+	// Pattern is the byte whose repetition the Hamming distance of every
+	// 8 bytes window is computed to.
+	Pattern byte
+
+	// Stride is how far each window is from the previous one it is
+	// compared to, so that runs repeating with a shorter period than the
+	// 8 bytes window, such as sentinel patterns, are detected.
+	Stride int
+}
+
+// Identity appends the options to dst, see chunkers.IdentityExtension.
+func (o *Options) Identity(dst []byte) []byte {
+	dst = binary.LittleEndian.AppendUint64(dst, uint64(o.LowEntropyThreshold))
+	dst = append(dst, o.Pattern)
+	return binary.LittleEndian.AppendUint64(dst, uint64(o.Stride))
+}
+
+// NewOptions returns the options of the paper.
+func NewOptions() *Options {
+	return &Options{
+		LowEntropyThreshold: 64,
+		Pattern:             0xAA,
+		Stride:              8,
+	}
+}
+
+var defaultOptions = NewOptions()
+
+// extension returns a copy of the Options in opts to modify, or those of
+// the paper, and sets it as the Extension of opts.
+func extension(opts *chunkers.ChunkerOpts) *Options {
+	ext := NewOptions()
+	if current, ok := opts.Extension.(*Options); ok {
+		*ext = *current
+	}
+	opts.Extension = ext
+	return ext
+}
+
+// WithLowEntropyThreshold sets the LowEntropyThreshold of the Extension.
+func WithLowEntropyThreshold(threshold int) chunkers.Option {
+	return func(opts *chunkers.ChunkerOpts) { extension(opts).LowEntropyThreshold = threshold }
+}
+
+// WithPattern sets the Pattern of the Extension.
+func WithPattern(pattern byte) chunkers.Option {
+	return func(opts *chunkers.ChunkerOpts) { extension(opts).Pattern = pattern }
+}
+
+// WithStride sets the Stride of the Extension.
+func WithStride(stride int) chunkers.Option {
+	return func(opts *chunkers.ChunkerOpts) { extension(opts).Stride = stride }
+}
 
 type UltraCDC struct {
+	flags chunkers.ChunkFlags
+
+	// Hamming distances to the pattern in use of the bytes substituted
+	// through the permutation derived from the key, if any
+	pattern      byte
+	key          []byte
+	substitution *[256]byte
+	distances    *[256]int
+
+	// scaled eases maskL for small chunks, as registered by v2
+	scaled bool
 }
 
 func newUltraCDC() chunkers.ChunkerImplementation {
 	return &UltraCDC{}
 }
 
+func newUltraCDCv2() chunkers.ChunkerImplementation {
+	return &UltraCDC{scaled: true}
+}
+
 func (c *UltraCDC) DefaultOptions() *chunkers.ChunkerOpts {
 	return &chunkers.ChunkerOpts{
 		MinSize:    2 * 1024,
@@ -62,9 +143,80 @@ func (c *UltraCDC) Validate(options *chunkers.ChunkerOpts) error {
 		options.MaxSize <= options.NormalSize {
 		return ErrMaxSize
 	}
+	if options.Extension != nil {
+		ext, ok := options.Extension.(*Options)
+		if !ok || ext.LowEntropyThreshold < 1 || ext.Stride < 1 || ext.Stride > 8 {
+			return ErrOptions
+		}
+	}
 	return nil
 }
 
+// substitutionFor returns the permutation of the bytes derived from key,
+// or nil if key is empty. Random bytes stay random once substituted, so
+// the distribution of the distances, and with it of the chunk sizes, is
+// that of the paper.
+func (c *UltraCDC) substitutionFor(key []byte) *[256]byte {
+	if bytes.Equal(c.key, key) && (len(key) == 0 || c.substitution != nil) {
+		return c.substitution
+	}
+	c.key = bytes.Clone(key)
+	c.substitution = nil
+	c.distances = nil
+	if len(key) == 0 {
+		return nil
+	}
+
+	seed := chunkers.DeriveKey(key, "ultracdc substitution")
+	rng := mathrand2.NewChaCha8(seed)
+	c.substitution = new([256]byte)
+	for b := range c.substitution {
+		c.substitution[b] = byte(b)
+	}
+	// Fisher-Yates, spelled out so that the permutation never depends on
+	// how math/rand/v2 implements Shuffle
+	for i := len(c.substitution) - 1; i > 0; i-- {
+		j := rng.Uint64() % uint64(i+1)
+		c.substitution[i], c.substitution[j] = c.substitution[j], c.substitution[i]
+	}
+	return c.substitution
+}
+
+// distancesTo returns the table of the Hamming distances to pattern, of
+// the bytes substituted through the permutation derived from key if set.
+func (c *UltraCDC) distancesTo(pattern byte, key []byte) *[256]int {
+	substitution := c.substitutionFor(key)
+	if c.distances == nil || c.pattern != pattern {
+		c.pattern = pattern
+		if pattern == 0xAA && substitution == nil {
+			c.distances = &hammingDistanceTo0xAA
+		} else {
+			c.distances = new([256]int)
+			for b := range c.distances {
+				v := byte(b)
+				if substitution != nil {
+					v = substitution[b]
+				}
+				c.distances[b] = bits.OnesCount8(v ^ pattern)
+			}
+		}
+	}
+	return c.distances
+}
+
+// Entropy reports the substitution derived from ChunkerOpts.Key, UltraCDC
+// depending on no random input otherwise.
+func (c *UltraCDC) Entropy(options *chunkers.ChunkerOpts) []chunkers.EntropyInput {
+	if len(options.Key) == 0 {
+		return nil
+	}
+	return []chunkers.EntropyInput{chunkers.NewEntropyInput("substitution", c.substitutionFor(options.Key))}
+}
+
+// Keyed marks the bytes as substituted through a permutation derived from
+// ChunkerOpts.Key before their distance to the pattern is computed.
+func (c *UltraCDC) Keyed() {}
+
 // Algorithm's return value, cutpoint, might typically be used next in
 // segment := data[:cutpoint], so we expect to exclude the cutpoint
 // index value itself. Also commonly when n == len(data) and data is
@@ -85,21 +237,33 @@ func (c *UltraCDC) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int)
 		panic(fmt.Sprintf("len(data) == %v and n == %v: n must be <= len(data)", len(data), n))
 	}
 
-	const (
-		maskS uint64 = 0x2F // binary 101111
+	const maskS uint64 = 0x2F // binary 101111
 
-		// maskL ignores 2 more bits than maskS, so
-		// it is easier to match (so we get a higher
-		// probability of match after the normal point).
-		maskL uint64 = 0x2C // binary 101100
+	// maskL ignores 2 more bits than maskS, so
+	// it is easier to match (so we get a higher
+	// probability of match after the normal point).
+	maskL := uint64(0x2C) // binary 101100
+	if c.scaled && options.NormalSize < 4*1024 && options.MaxSize-options.NormalSize <= 6*1024 {
+		// Cuts mostly happen about 1.3KiB past the normal point with
+		// the mask of the paper, too many small chunks would reach
+		// MaxSize first. Ignoring one more bit brings it to about 100
+		// bytes.
+		maskL = 0x28 // binary 101000
+	}
+	ext, ok := options.Extension.(*Options)
+	if !ok {
+		ext = defaultOptions
+	}
+	lowEntropyStringThreshold := ext.LowEntropyThreshold // LEST in the paper.
+	stride := ext.Stride
+	hammingDistanceToPattern := c.distancesTo(ext.Pattern, options.Key)
 
-		lowEntropyStringThreshold int = 64 // LEST in the paper.
-	)
 	minSize := options.MinSize
 	maxSize := options.MaxSize
 	normalSize := options.NormalSize
 
 	var lowEntropyCount int
+	c.flags = 0
 
 	// initial mask for small cuts below the Normal point.
 	mask := maskS
@@ -114,19 +278,39 @@ func (c *UltraCDC) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int)
 		normalSize = n
 	}
 
-	outBufWin := data[minSize : minSize+8]
+	if n < minSize+8 {
+		// no window fits before n
+		cutpoint = n
+		if n == maxSize {
+			c.flags = chunkers.FlagForced
+		}
+		return
+	}
+
+	// Windows are loaded as 64-bit words, compared in one instruction
+	// rather than through bytes.Equal. Unless bytes are substituted, the
+	// distance of a whole window to the pattern, the 0xAAAAAAAAAAAAAAAA
+	// of the paper by default, is a single POPCNT where the window is
+	// not rolled byte by byte.
+	words := len(options.Key) == 0
+	pattern := uint64(ext.Pattern) * 0x0101010101010101
+
+	outBufWin := binary.LittleEndian.Uint64(data[minSize:])
 
 	// Initialize hamming distance on outBufWin
 	dist := 0
-	for _, v := range outBufWin {
-		// effectively the Pattern of 0xAAAAAAAAAAAAAAAA,
-		// as referenced in the paper,
-		// is expressed here, just one byte at a time.
-		dist += bits.OnesCount8(v ^ 0xAA)
+	if words {
+		dist = bits.OnesCount64(outBufWin ^ pattern)
+	} else {
+		for _, v := range data[minSize : minSize+8] {
+			dist += hammingDistanceToPattern[v]
+		}
 	}
 
-	var inBufWin []byte
-	for i := minSize + 8; i <= n-8; i += 8 {
+	// outBufWin is the window stride bytes before inBufWin, dist the
+	// distance of the 8 bytes before position i+j.
+	var inBufWin uint64
+	for i := minSize + 8; i <= n-8; i += stride {
 		if i >= normalSize {
 			// Yes, we write mask every time after the Normal point,
 			// and at first this appears wasteful. However,
@@ -148,9 +332,9 @@ func (c *UltraCDC) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int)
 
 		// If i == n-8 then i+8 == n, and since n <= len(data)
 		// as a PRE condition, we never go out of bounds.
-		inBufWin = data[i : i+8]
+		inBufWin = binary.LittleEndian.Uint64(data[i:])
 
-		if bytes.Equal(inBufWin, outBufWin) {
+		if inBufWin == outBufWin {
 			lowEntropyCount++
 			if lowEntropyCount >= lowEntropyStringThreshold {
 				// on random (high-entropy) data, we don't expect to get here.
@@ -158,13 +342,24 @@ func (c *UltraCDC) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int)
 				// If i == n-8, its largest, then this returns n,
 				// which maintains our POST INVARIANT that cutpoint <= n.
 				cutpoint = i + 8
+				c.flags = chunkers.FlagLowEntropy
 				return
 			}
+			// with the default stride of 8, the bytes rolled in and
+			// out are equal and dist stays as is
+			if words {
+				dist = bits.OnesCount64(binary.LittleEndian.Uint64(data[i+stride-8:]) ^ pattern)
+			} else {
+				for j := 0; j < stride; j++ {
+					dist += hammingDistanceToPattern[data[i+j]] - hammingDistanceToPattern[data[i+j-8]]
+				}
+			}
+			outBufWin = inBufWin
 			continue
 		}
 
 		lowEntropyCount = 0
-		for j := 0; j < 8; j++ {
+		for j := 0; j < stride; j++ {
 			if (uint64(dist) & mask) == 0 {
 				// Do we preserve the POST INVARIANT here?
 				// if i == n-8 (the biggest possible), and
@@ -190,8 +385,11 @@ func (c *UltraCDC) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int)
 			//
 			// https://stackoverflow.com/questions/28802692/how-is-popcnt-implemented-in-hardware
 			//
-			//update := bits.OnesCount8(inByte^0xAA) - bits.OnesCount8(outByte^0xAA)
-			update := hammingDistanceTo0xAA[inByte] - hammingDistanceTo0xAA[outByte]
+			// Recomputing the distance of the whole window with
+			// bits.OnesCount64 at every byte measured slower still.
+			//
+			//update := bits.OnesCount8(inByte^ext.Pattern) - bits.OnesCount8(outByte^ext.Pattern)
+			update := hammingDistanceToPattern[inByte] - hammingDistanceToPattern[outByte]
 			dist += update
 		}
 		outBufWin = inBufWin
@@ -199,5 +397,13 @@ func (c *UltraCDC) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int)
 
 	// obviously preserves the POST INVARIANT that cutpoint <= n.
 	cutpoint = n
+	if n == maxSize {
+		c.flags = chunkers.FlagForced
+	}
 	return
 }
+
+// Flags reports whether the last cutpoint was forced or low-entropy.
+func (c *UltraCDC) Flags() chunkers.ChunkFlags {
+	return c.flags
+}
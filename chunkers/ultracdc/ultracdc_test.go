@@ -17,6 +17,7 @@
 package ultracdc
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
@@ -269,3 +270,99 @@ var expectedCuts = []int{
 	1016752, 1017028, 1017782, 1023265, 1027555, 1035555, 1038852, 1039386,
 	1045847, 1047729, 1047858, 1048577,
 }
+
+func Test_Options(t *testing.T) {
+	var seed [32]byte
+	random := make([]byte, 1<<20)
+	mathrand2.NewChaCha8(seed).Read(random)
+	zeroes := make([]byte, 1<<20)
+
+	c := newUltraCDC()
+	opts := c.DefaultOptions()
+	cut := func(ext *Options, data []byte) int {
+		opts.Extension = nil
+		if ext != nil {
+			opts.Extension = ext
+		}
+		if err := c.Validate(opts); err != nil {
+			t.Fatalf(`options rejected: %s`, err)
+		}
+		return c.Algorithm(opts, data, len(data))
+	}
+
+	// the options of the paper are the defaults
+	for _, data := range [][]byte{random, zeroes} {
+		for offset := 0; offset < len(data)-opts.MaxSize; {
+			cutpoint := cut(nil, data[offset:])
+			if explicit := cut(NewOptions(), data[offset:]); explicit != cutpoint {
+				t.Fatalf(`offset %d: cutpoint %d with the paper's options, %d by default`, offset, explicit, cutpoint)
+			}
+			offset += cutpoint
+		}
+	}
+
+	// a low-entropy cut happens after the threshold of equal windows
+	if cutpoint := cut(&Options{LowEntropyThreshold: 8, Pattern: 0xAA, Stride: 8}, zeroes); cutpoint != opts.MinSize+8*8+8 {
+		t.Fatalf(`expected a low-entropy cut at %d, got %d`, opts.MinSize+8*8+8, cutpoint)
+	}
+
+	// a 3 bytes period only shows with a stride multiple of 3
+	sentinel := bytes.Repeat([]byte{0xde, 0xad, 0x00}, 1<<18)
+	cut(nil, sentinel)
+	if c.(*UltraCDC).Flags() == chunkers.FlagLowEntropy {
+		t.Fatalf(`unexpected low-entropy cut with a stride of 8`)
+	}
+	cut(&Options{LowEntropyThreshold: 64, Pattern: 0xAA, Stride: 3}, sentinel)
+	if c.(*UltraCDC).Flags() != chunkers.FlagLowEntropy {
+		t.Fatalf(`expected a low-entropy cut with a stride of 3`)
+	}
+
+	// another pattern selects other cutpoints
+	if cut(nil, random) == cut(&Options{LowEntropyThreshold: 64, Pattern: 0x0F, Stride: 8}, random) {
+		t.Fatalf(`the pattern has no effect`)
+	}
+
+	opts.Extension = &Options{LowEntropyThreshold: 64, Pattern: 0xAA, Stride: 9}
+	if err := c.Validate(opts); err != ErrOptions {
+		t.Fatalf(`expected ErrOptions, got %v`, err)
+	}
+	opts.Extension = struct{}{}
+	if err := c.Validate(opts); err != ErrOptions {
+		t.Fatalf(`expected ErrOptions, got %v`, err)
+	}
+}
+
+func Benchmark_Algorithm(b *testing.B) {
+	data := make([]byte, 64<<20)
+	rng := mathrand2.New(mathrand2.NewPCG(1, 2))
+	for i := range data {
+		data[i] = byte(rng.Uint32())
+	}
+
+	for _, key := range [][]byte{nil, []byte("secret")} {
+		b.Run(fmt.Sprintf("keyed=%v", key != nil), func(b *testing.B) {
+			u := newUltraCDC().(*UltraCDC)
+			opts := u.DefaultOptions()
+			opts.Key = key
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				for rest := data; len(rest) != 0; {
+					cutpoint := u.Algorithm(opts, rest, min(len(rest), opts.MaxSize))
+					rest = rest[cutpoint:]
+				}
+			}
+		})
+	}
+}
+
+// Tails shorter than a window past MinSize are never read beyond n.
+func Test_Short_Tail(t *testing.T) {
+	u := newUltraCDC().(*UltraCDC)
+	opts := u.DefaultOptions()
+	for n := opts.MinSize + 1; n < opts.MinSize+16; n++ {
+		data := make([]byte, n)
+		if cutpoint := u.Algorithm(opts, data, n); cutpoint != n {
+			t.Fatalf(`cutpoint %d for a tail of %d bytes`, cutpoint, n)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package winnowing
+
+import (
+	"errors"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+)
+
+func init() {
+	chunkers.MustRegister("winnowing", newWinnowing)
+}
+
+var ErrNormalSize = errors.New("NormalSize is required and must be 64B <= NormalSize <= 1GB")
+var ErrMinSize = errors.New("MinSize is required and must be 128B <= MinSize <= 1GB && MinSize < NormalSize")
+var ErrMaxSize = errors.New("MaxSize is required and must be 64B <= MaxSize <= 1GB && MaxSize > NormalSize")
+var ErrWindow = errors.New("Window must be 0 <= Window <= MinSize - 64")
+
+// gearWindow is the number of bytes a Gear fingerprint depends on
+const gearWindow = 64
+
+// Winnowing applies the winnowing scheme of Schleimer, Wilkerson and Aiken
+// to chunking: every position is hashed with the Gear fingerprint of the
+// 64 bytes before it, and in every window of Window consecutive positions
+// the rightmost one holding the minimum hash is selected. A cut happens at
+// the first position selected past MinSize, which winnowing guarantees
+// before MinSize + Window, so chunks never exceed that size whatever the
+// content.
+//
+// Windows only reach back to bytes of the current chunk, so Window cannot
+// exceed MinSize - 64. On random data the cut falls about Window/3 past
+// MinSize, so the default window is derived from NormalSize - MinSize
+// within that limit, and NormalSize is only reached with a MinSize of at
+// least 3/4 of it.
+type Winnowing struct {
+	// monotonic deque of the positions whose hash may still be the
+	// minimum of a window, with increasing hashes
+	positions []int
+	hashes    []uint64
+
+	keyed fastcdc.KeyedTable
+}
+
+// Options tunes winnowing when passed as ChunkerOpts.Extension.
+type Options struct {
+	// Window is the number of consecutive positions the minimum hash is
+	// selected among, zero deriving it from NormalSize - MinSize.
+	Window int
+}
+
+// extension returns a copy of the Options in opts to modify, and sets it
+// as the Extension of opts.
+func extension(opts *chunkers.ChunkerOpts) *Options {
+	ext := &Options{}
+	if current, ok := opts.Extension.(*Options); ok {
+		*ext = *current
+	}
+	opts.Extension = ext
+	return ext
+}
+
+// WithWindow sets the Window of the Extension.
+func WithWindow(window int) chunkers.Option {
+	return func(opts *chunkers.ChunkerOpts) { extension(opts).Window = window }
+}
+
+// configuredWindow returns the window set for options, the one of the
+// Extension taking precedence, or zero.
+func configuredWindow(options *chunkers.ChunkerOpts) int {
+	if ext, ok := options.Extension.(*Options); ok && ext.Window != 0 {
+		return ext.Window
+	}
+	return options.Window
+}
+
+func newWinnowing() chunkers.ChunkerImplementation {
+	return &Winnowing{}
+}
+
+func (c *Winnowing) DefaultOptions() *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{
+		MinSize:    6 * 1024,
+		MaxSize:    64 * 1024,
+		NormalSize: 8 * 1024,
+	}
+}
+
+func (c *Winnowing) Validate(options *chunkers.ChunkerOpts) error {
+	if options.NormalSize < 64 || options.NormalSize > 1024*1024*1024 {
+		return ErrNormalSize
+	}
+	if options.MinSize < 2*gearWindow || options.MinSize > 1024*1024*1024 || options.MinSize >= options.NormalSize {
+		return ErrMinSize
+	}
+	if options.MaxSize < 64 || options.MaxSize > 1024*1024*1024 || options.MaxSize <= options.NormalSize {
+		return ErrMaxSize
+	}
+	if window := configuredWindow(options); window < 0 || window > options.MinSize-gearWindow {
+		return ErrWindow
+	}
+	return nil
+}
+
+// window returns the window size in effect for options
+func window(options *chunkers.ChunkerOpts) int {
+	if window := configuredWindow(options); window != 0 {
+		return window
+	}
+	return max(1, min(3*(options.NormalSize-options.MinSize), options.MinSize-gearWindow))
+}
+
+// ResolveOptions sets the Window in effect in the Extension.
+func (c *Winnowing) ResolveOptions(options *chunkers.ChunkerOpts) {
+	extension(options).Window = window(options)
+	options.Window = 0
+}
+
+// Entropy reports the Gear table shared with fastcdc.
+func (c *Winnowing) Entropy(options *chunkers.ChunkerOpts) []chunkers.EntropyInput {
+	return []chunkers.EntropyInput{chunkers.NewEntropyInput("gear table", c.keyed.Table(options.Key))}
+}
+
+// Keyed marks the Gear table as derived from ChunkerOpts.Key.
+func (c *Winnowing) Keyed() {}
+
+func (c *Winnowing) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	MinSize := options.MinSize
+	MaxSize := options.MaxSize
+
+	switch {
+	case n <= MinSize:
+		return n
+	case n >= MaxSize:
+		n = MaxSize
+	}
+
+	w := window(options)
+	if len(c.positions) != w {
+		c.positions = make([]int, w)
+		c.hashes = make([]uint64, w)
+	}
+
+	// the deque is a ring of w entries, as it never holds more than a
+	// window worth of positions
+	head, length := 0, 0
+
+	g := c.keyed.Table(options.Key)
+	first := MinSize - w + 1
+	fp := uint64(0)
+	for i := first - gearWindow; i < first-1; i++ {
+		fp = (fp << 1) + g[data[i]]
+	}
+	for i := first; i <= n; i++ {
+		// the hash of position i covers the bytes before it
+		fp = (fp << 1) + g[data[i-1]]
+
+		for length > 0 && c.hashes[(head+length-1)%w] >= fp {
+			length--
+		}
+		c.positions[(head+length)%w] = i
+		c.hashes[(head+length)%w] = fp
+		length++
+		if c.positions[head] <= i-w {
+			head = (head + 1) % w
+			length--
+		}
+
+		if i >= MinSize && c.positions[head] >= MinSize {
+			return c.positions[head]
+		}
+	}
+	return n
+}
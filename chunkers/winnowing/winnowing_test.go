@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package winnowing
+
+import (
+	mathrand2 "math/rand/v2"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+)
+
+// hashes returns the hash of every position of a chunk, zero where fewer
+// than 64 bytes precede it
+func hashes(chunk []byte) []uint64 {
+	h := make([]uint64, len(chunk)+1)
+	for i := gearWindow; i <= len(chunk); i++ {
+		fp := uint64(0)
+		for _, b := range chunk[i-gearWindow : i] {
+			fp = (fp << 1) + fastcdc.G[b]
+		}
+		h[i] = fp
+	}
+	return h
+}
+
+func Test_Cutpoints(t *testing.T) {
+	var seed [32]byte
+	data := make([]byte, 16<<20)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	c := newWinnowing()
+	opts := c.DefaultOptions()
+	if err := c.Validate(opts); err != nil {
+		t.Fatalf(`default options: %s`, err)
+	}
+	w := window(opts)
+
+	chunks := 0
+	for remaining := data; len(remaining) > 0; chunks++ {
+		cutpoint := c.Algorithm(opts, remaining, min(len(remaining), opts.MaxSize))
+		if cutpoint == len(remaining) {
+			break
+		}
+		if cutpoint < opts.MinSize || cutpoint >= opts.MinSize+w {
+			t.Fatalf(`cutpoint %d outside of [%d, %d)`, cutpoint, opts.MinSize, opts.MinSize+w)
+		}
+
+		// the cut is the rightmost minimum of a window ending at or
+		// after it, and no earlier position past MinSize is one
+		if chunks < 100 {
+			h := hashes(remaining[:opts.MinSize+w])
+			selected := func(p int) bool {
+				for end := p; end < p+w && end < len(h); end++ {
+					start := end - w + 1
+					if start < opts.MinSize-w+1 {
+						continue
+					}
+					minimum := true
+					for j := start; j <= end; j++ {
+						if (j < p && h[j] < h[p]) || (j > p && h[j] <= h[p]) {
+							minimum = false
+							break
+						}
+					}
+					if minimum {
+						return true
+					}
+				}
+				return false
+			}
+			if !selected(cutpoint) {
+				t.Fatalf(`cutpoint %d is not selected by winnowing`, cutpoint)
+			}
+		}
+		remaining = remaining[cutpoint:]
+	}
+
+	if avg := len(data) / chunks; avg < opts.NormalSize*7/8 || avg > opts.NormalSize*9/8 {
+		t.Fatalf(`average chunk size %d too far from %d`, avg, opts.NormalSize)
+	}
+
+	if err := c.Validate(&chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, Window: 2 << 10}); err != ErrWindow {
+		t.Fatalf(`expected ErrWindow, got %v`, err)
+	}
+}
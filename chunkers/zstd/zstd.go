@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package zstd
+
+import (
+	"errors"
+	"math/bits"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func init() {
+	chunkers.MustRegister("zstd-rsyncable", newRsyncable)
+}
+
+var ErrNormalSize = errors.New("NormalSize is required and must be 64B <= NormalSize <= 1GB")
+var ErrMinSize = errors.New("MinSize must be 32B <= MinSize < NormalSize")
+var ErrMaxSize = errors.New("MaxSize is required and must be MaxSize <= 1GB && MaxSize >= NormalSize")
+
+const (
+	rsyncLength = 32                 // RSYNC_LENGTH
+	prime8bytes = 0xCF1BBCDCB7A56463 // prime8bytes
+	charOffset  = 10                 // ZSTD_ROLL_HASH_CHAR_OFFSET
+)
+
+// primePower is prime8bytes^(rsyncLength-1), which rolls a byte out of
+// the hash, as ZSTD_rollingHash_primePower computes it.
+var primePower = func() uint64 {
+	power := uint64(1)
+	for i := 0; i < rsyncLength-1; i++ {
+		power *= prime8bytes
+	}
+	return power
+}()
+
+// Rsyncable reproduces the synchronization points of zstd --rsyncable,
+// where multithreaded zstd ends a job and flushes so that the compressed
+// stream resynchronizes after a change: the rolling hash of the last 32
+// bytes is tested once a job holds more than 128KiB, and a job ends where
+// its low bits are all ones. zstd derives the number of bits from its job
+// size, floor(log2(NormalSize)) here, and ends jobs at the job size when
+// no synchronization point was found, which MaxSize stands for. The
+// defaults match the 8MiB jobs of the default compression level, and
+// chunk stores aligned on these boundaries resynchronize with the stream.
+type Rsyncable struct {
+}
+
+func newRsyncable() chunkers.ChunkerImplementation {
+	return &Rsyncable{}
+}
+
+func (c *Rsyncable) DefaultOptions() *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{
+		MinSize:    128 * 1024,
+		NormalSize: 8 * 1024 * 1024,
+		MaxSize:    8 * 1024 * 1024,
+	}
+}
+
+func (c *Rsyncable) Validate(options *chunkers.ChunkerOpts) error {
+	if options.NormalSize < 64 || options.NormalSize > 1024*1024*1024 {
+		return ErrNormalSize
+	}
+	if options.MinSize < rsyncLength || options.MinSize >= options.NormalSize {
+		return ErrMinSize
+	}
+	if options.MaxSize > 1024*1024*1024 || options.MaxSize < options.NormalSize {
+		return ErrMaxSize
+	}
+	return nil
+}
+
+func (c *Rsyncable) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	MinSize := options.MinSize
+	MaxSize := options.MaxSize
+
+	switch {
+	case n <= MinSize:
+		return n
+	case n >= MaxSize:
+		n = MaxSize
+	}
+
+	hitMask := uint64(1)<<(bits.Len(uint(options.NormalSize))-1) - 1
+
+	// ZSTD_rollingHash_compute over the window ending at MinSize, the
+	// first position is only tested once a byte was rolled in
+	hash := uint64(0)
+	for _, b := range data[MinSize-rsyncLength : MinSize] {
+		hash = hash*prime8bytes + uint64(b) + charOffset
+	}
+	for i := MinSize; i < n; i++ {
+		hash -= (uint64(data[i-rsyncLength]) + charOffset) * primePower
+		hash = hash*prime8bytes + uint64(data[i]) + charOffset
+		if hash&hitMask == hitMask {
+			return i + 1
+		}
+	}
+	return n
+}
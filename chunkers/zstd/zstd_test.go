@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package zstd
+
+import (
+	mathrand2 "math/rand/v2"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+// rollingHash is ZSTD_rollingHash_compute, hashing a window from scratch
+// as a reference for the rolling updates.
+func rollingHash(window []byte) uint64 {
+	hash := uint64(0)
+	for _, b := range window {
+		hash *= prime8bytes
+		hash += uint64(b) + charOffset
+	}
+	return hash
+}
+
+func Test_Matches_Reference(t *testing.T) {
+	var seed [32]byte
+	data := make([]byte, 4<<20)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	c := newRsyncable()
+	opts := &chunkers.ChunkerOpts{MinSize: 1024, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	if err := c.Validate(opts); err != nil {
+		t.Fatalf(`options rejected: %s`, err)
+	}
+
+	chunks := 0
+	for remaining := data; len(remaining) > 0; chunks++ {
+		n := min(len(remaining), opts.MaxSize)
+		expected := n
+		if n > opts.MinSize {
+			for end := opts.MinSize + 1; end <= n; end++ {
+				if rollingHash(remaining[end-rsyncLength:end])&(8<<10-1) == 8<<10-1 {
+					expected = end
+					break
+				}
+			}
+		}
+		if cutpoint := c.Algorithm(opts, remaining, n); cutpoint != expected {
+			t.Fatalf(`chunk %d: expected cutpoint %d, got %d`, chunks, expected, cutpoint)
+		}
+		remaining = remaining[expected:]
+	}
+	if average := len(data) / chunks; average < 4<<10 || average > 16<<10 {
+		t.Fatalf(`unexpected average chunk size %d`, average)
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	c := newRsyncable()
+	if err := c.Validate(c.DefaultOptions()); err != nil {
+		t.Fatalf(`default options rejected: %s`, err)
+	}
+
+	opts := c.DefaultOptions()
+	opts.MinSize = 16
+	if err := c.Validate(opts); err != ErrMinSize {
+		t.Fatalf(`expected ErrMinSize, got %v`, err)
+	}
+}
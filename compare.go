@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+import (
+	"bytes"
+	"crypto/sha256"
+)
+
+type AlgorithmReport struct {
+	Algorithm   string
+	Chunks      int
+	AverageSize float64
+
+	// DedupRatio is the fraction of the mutated input made of chunks
+	// that already appeared when chunking the original input.
+	DedupRatio float64
+}
+
+type Comparison struct {
+	A AlgorithmReport
+	B AlgorithmReport
+
+	// SharedBoundaries counts cutpoints both algorithms picked in the
+	// original input, BoundaryOverlap relates it to all distinct
+	// cutpoints picked by either of them.
+	SharedBoundaries int
+	BoundaryOverlap  float64
+}
+
+// Compare chunks original and mutated with two algorithms and reports
+// how their boundaries relate and how well each deduplicates the mutated
+// copy against the original. A nil opts uses each algorithm's defaults.
+func Compare(algorithmA, algorithmB string, opts *ChunkerOpts, original, mutated []byte) (*Comparison, error) {
+	boundariesA, reportA, err := compareAlgorithm(algorithmA, opts, original, mutated)
+	if err != nil {
+		return nil, err
+	}
+	boundariesB, reportB, err := compareAlgorithm(algorithmB, opts, original, mutated)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &Comparison{A: *reportA, B: *reportB}
+	for boundary := range boundariesA {
+		if _, exists := boundariesB[boundary]; exists {
+			comparison.SharedBoundaries++
+		}
+	}
+	if union := len(boundariesA) + len(boundariesB) - comparison.SharedBoundaries; union != 0 {
+		comparison.BoundaryOverlap = float64(comparison.SharedBoundaries) / float64(union)
+	}
+	return comparison, nil
+}
+
+func compareAlgorithm(algorithm string, opts *ChunkerOpts, original, mutated []byte) (map[uint64]struct{}, *AlgorithmReport, error) {
+	boundaries := make(map[uint64]struct{})
+	digests := make(map[[32]byte]struct{})
+	report := &AlgorithmReport{Algorithm: algorithm}
+
+	chunker, err := NewChunker(algorithm, bytes.NewReader(original), opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		boundaries[offset+length] = struct{}{}
+		digests[sha256.Sum256(chunk)] = struct{}{}
+		report.Chunks++
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if report.Chunks != 0 {
+		report.AverageSize = float64(len(original)) / float64(report.Chunks)
+	}
+
+	chunker, err = NewChunker(algorithm, bytes.NewReader(mutated), opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	deduplicated := 0
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		if _, exists := digests[sha256.Sum256(chunk)]; exists {
+			deduplicated += len(chunk)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(mutated) != 0 {
+		report.DedupRatio = float64(deduplicated) / float64(len(mutated))
+	}
+
+	return boundaries, report, nil
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+import (
+	"errors"
+)
+
+var ErrStateful = errors.New("algorithm depends on previous chunks and cannot cut buffers on their own")
+
+// Compiled holds options validated once for an algorithm, to find the
+// cutpoints of buffers held in memory without a Chunker. It is not safe
+// for concurrent use.
+type Compiled struct {
+	options        *ChunkerOpts
+	implementation ChunkerImplementation
+}
+
+// Compile validates the options for an algorithm, nil options selecting
+// its defaults, and returns them ready for FindCutpoint. Algorithms that
+// depend on previous chunks are refused. The options must not be modified
+// afterwards.
+func (opts *ChunkerOpts) Compile(algorithm string) (*Compiled, error) {
+	algorithm, implementationAllocator, exists := lookup(algorithm)
+	if !exists {
+		return nil, ErrUnknownAlgorithm
+	}
+	if opts == nil {
+		opts = defaultOptions(algorithm, implementationAllocator)
+	}
+
+	implementation := implementationAllocator()
+	if _, stateful := implementation.(StatefulImplementation); stateful {
+		return nil, ErrStateful
+	}
+	if err := validate(implementation, opts); err != nil {
+		return nil, err
+	}
+	return &Compiled{options: opts, implementation: implementation}, nil
+}
+
+// FindCutpoint returns the length of the chunk starting data, at most
+// MaxSize bytes, which is all of data if it is shorter than MinSize. data
+// is taken as followed by more of the stream: Hints, Scanner and
+// MinTailSize do not apply.
+func (c *Compiled) FindCutpoint(data []byte) int {
+	if len(data) == 0 {
+		return 0
+	}
+	return c.implementation.Algorithm(c.options, data, min(len(data), c.options.MaxSize))
+}
+
+// FindCutpoint compiles the options for an algorithm and returns the
+// cutpoint of data, for one-off calls. See Compile and
+// Compiled.FindCutpoint.
+func FindCutpoint(algorithm string, opts *ChunkerOpts, data []byte) (int, error) {
+	compiled, err := opts.Compile(algorithm)
+	if err != nil {
+		return 0, err
+	}
+	return compiled.FindCutpoint(data), nil
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+type DedupStats struct {
+	Chunks uint64
+	Bytes  uint64
+
+	DuplicateChunks uint64
+	DuplicateBytes  uint64
+
+	// RepeatedChunks counts the duplicates flagged with FlagRepeat, for
+	// which has was not consulted.
+	RepeatedChunks uint64
+}
+
+// Ratio returns the fraction of bytes found to be duplicates so far.
+func (s DedupStats) Ratio() float64 {
+	if s.Bytes == 0 {
+		return 0
+	}
+	return float64(s.DuplicateBytes) / float64(s.Bytes)
+}
+
+// SplitDedup behaves like Split but computes the digest of every chunk,
+// see ChunkerOpts.Hash, and consults has to tell whether it was seen
+// before. Running
+// totals are reported to stats, which may be nil, before callback is
+// invoked for the chunk. Chunks flagged with FlagRepeat were handed to
+// callback moments ago and are counted as duplicates without consulting
+// has.
+func (chunker *Chunker) SplitDedup(has func(digest []byte) bool, stats func(DedupStats), callback func(offset, length uint64, chunk []byte) error) error {
+	var s DedupStats
+	return chunker.SplitDigest(func(offset, length uint64, chunk []byte, digest []byte) error {
+		s.Chunks++
+		s.Bytes += uint64(length)
+		switch {
+		case chunker.flags&FlagRepeat != 0:
+			s.RepeatedChunks++
+			fallthrough
+		case has(digest):
+			s.DuplicateChunks++
+			s.DuplicateBytes += uint64(length)
+		}
+		if stats != nil {
+			stats(s)
+		}
+		return callback(offset, length, chunk)
+	})
+}
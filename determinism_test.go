@@ -0,0 +1,175 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	mathrand2 "math/rand/v2"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/bupsplit"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/casync"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/fixed"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/gear"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/jc"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/maxp"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/mii"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/pci"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/quickcdc"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/rapidcdc"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/restic"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/rsync"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/seqcdc"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/sourcecode"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/ultracdc"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/winnowing"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/zstd"
+)
+
+// Boundaries must be identical on every platform or dedup silently breaks
+// across a mixed fleet. Each vector is the SHA-256 of the cutpoints, as
+// little-endian uint64, of 4MiB of deterministic data. If an algorithm is
+// changed on purpose, set regenerate = true for one run and paste the
+// output below.
+var determinismVectors = []struct {
+	algorithm string
+	opts      *chunkers.ChunkerOpts
+	expected  string
+}{
+	{"fastcdc", nil, "c9aa2b5b80a6788560e26c632e30220cc70eefc4fc013a013d753856f6416d63"},
+	{"fastcdc", &chunkers.ChunkerOpts{MinSize: 64 << 10, NormalSize: 256 << 10, MaxSize: 1 << 20}, "e883d26b2337d5db02190fdca9efa27b1de54496ed76bb141eb57e2ff994da94"},
+	{"fastcdc", &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, NormalizationLevel: -1}, "db8a6f2bf5b76d587611fec171b842cd2e3e2b7867aa6c77d6edd20fe3a6b2b4"},
+	{"fastcdc", &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, NormalizationLevel: 3}, "43749db09ce9955f3e832bd38cd2c7043cc75f197fa7f713c9d223468daf8867"},
+	// v1 cuts with the masks of the paper whatever NormalSize, these
+	// vectors were produced by the release preceding v2
+	{"fastcdc", &chunkers.ChunkerOpts{MinSize: 512, NormalSize: 2 << 10, MaxSize: 16 << 10}, "c2cb9039648defe9a616da2eeb2330ed08a5892b3d05615f6f9b829ec371bd40"},
+	{"fastcdc", &chunkers.ChunkerOpts{MinSize: 256, NormalSize: 1 << 10, MaxSize: 8 << 10}, "8516bbd36e11c6d800979c390a1c7973bca8b09ad2f4f54f3f446d5c109c724b"},
+	{"fastcdc", &chunkers.ChunkerOpts{MinSize: 512 << 10, NormalSize: 4 << 20, MaxSize: 8 << 20}, "f576c0d3e411da16babad0f0fb462a08492cd17812cd99293b6618400c010e08"},
+	{"fastcdc2020", &chunkers.ChunkerOpts{MinSize: 512, NormalSize: 2 << 10, MaxSize: 16 << 10}, "c2cb9039648defe9a616da2eeb2330ed08a5892b3d05615f6f9b829ec371bd40"},
+	{"fastcdc2020", &chunkers.ChunkerOpts{MinSize: 512 << 10, NormalSize: 4 << 20, MaxSize: 8 << 20}, "f576c0d3e411da16babad0f0fb462a08492cd17812cd99293b6618400c010e08"},
+	{"fastcdc@v2", &chunkers.ChunkerOpts{MinSize: 512, NormalSize: 2 << 10, MaxSize: 16 << 10}, "d771e56a7b659a4a503d6d2c2eec9ec83d69a7941a5858eb0e0b4e64e09b5e7b"},
+	{"fastcdc@v2", &chunkers.ChunkerOpts{MinSize: 512 << 10, NormalSize: 4 << 20, MaxSize: 8 << 20}, "0854082c3b0245f7e0f1cc127fe74d2efe6a72eaa875378afa350adba710186d"},
+	{"fastcdc2020@v2", &chunkers.ChunkerOpts{MinSize: 512, NormalSize: 2 << 10, MaxSize: 16 << 10}, "d771e56a7b659a4a503d6d2c2eec9ec83d69a7941a5858eb0e0b4e64e09b5e7b"},
+	{"fastcdc2020", nil, "c9aa2b5b80a6788560e26c632e30220cc70eefc4fc013a013d753856f6416d63"},
+	{"fastcdc2020", &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, NormalizationLevel: 3}, "43749db09ce9955f3e832bd38cd2c7043cc75f197fa7f713c9d223468daf8867"},
+	{"jc", nil, "c8ba1da0a77a41a02dfcc456a8b833f332b4cb11493672a04e6d72cd6190452c"},
+	{"jc", &chunkers.ChunkerOpts{MinSize: 64 << 10, NormalSize: 256 << 10, MaxSize: 1 << 20}, "1a9e13c322ae5ce7fbfa6dce5dbe5bf1c12ae46a5e69dafa5f96f40922ab8461"},
+	{"jc", &chunkers.ChunkerOpts{MinSize: 512, NormalSize: 2 << 10, MaxSize: 16 << 10}, "ae5a40b3a6ce3f945fba061f3f0dc22c8bb38068ac5fe568e149da5f89898c1a"},
+	{"jc", &chunkers.ChunkerOpts{MinSize: 512 << 10, NormalSize: 4 << 20, MaxSize: 8 << 20}, "fe20de2196d927c683a52b34cbe0006684c01948f72023a202b81305733a5f4e"},
+	{"jc@v2", &chunkers.ChunkerOpts{MinSize: 512, NormalSize: 2 << 10, MaxSize: 16 << 10}, "a347fba1403cfb7aa8c0d3e0edee99d88c5b24952c357aaf17aee932c8b8e0bf"},
+	{"jc@v2", &chunkers.ChunkerOpts{MinSize: 512 << 10, NormalSize: 4 << 20, MaxSize: 8 << 20}, "bdff8beff5fe5abfc09e53fd98a3b4f6e29dd07015f25f14091685b7b22fc19d"},
+	{"ultracdc", nil, "ecf66989588db4e743bcac94a3ded1c39664ebae76e6a61d61e7052fb8639b1e"},
+	{"ultracdc", &chunkers.ChunkerOpts{MinSize: 64 << 10, NormalSize: 256 << 10, MaxSize: 1 << 20}, "4686fa6cf0f0f5fa92d78cabf8ae9b76d803e555c09edb2d4c0a08f5c0745fd6"},
+	{"ultracdc", &chunkers.ChunkerOpts{MinSize: 512, NormalSize: 2 << 10, MaxSize: 8 << 10}, "29d6a22a9d3058509adde1d73fc60a4084a65686e7d8cf67373607c9ae06840c"},
+	{"ultracdc@v2", &chunkers.ChunkerOpts{MinSize: 512, NormalSize: 2 << 10, MaxSize: 8 << 10}, "80595ada94e421ddb6f62cfb57a0e7629511368f81c1c1901121b3c984a192dc"},
+	{"bupsplit", nil, "23288df0a1f36d0ccc0fd5b6da98adfaaa451ea89df0a59b9c4223024fcaa96e"},
+	{"bupsplit", &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 64 << 10, MaxSize: 256 << 10}, "510ef195b9c5dc73676d3d5871f1e01b89699c71a29022bc87e7347ae17925be"},
+	{"gear", nil, "fa07101b5be4a31cc4fffac88ae69c1bea988e375143463d526d3f5381c946e4"},
+	{"gear", &chunkers.ChunkerOpts{MinSize: 64 << 10, NormalSize: 256 << 10, MaxSize: 1 << 20}, "571a164ae4b8676dace2ab3544242f81f4625f5de0b1fc8accccc735bcefdd90"},
+	{"mii", nil, "37465837a3e680b2f043c7bd79db873afca2970e0a9d6bf0775fb23d77d87ec8"},
+	{"mii", &chunkers.ChunkerOpts{MinSize: 64 << 10, NormalSize: 256 << 10, MaxSize: 1 << 20}, "8356ff4a2723c9248c1bc09df8aaac899f42e5ed2b1adc847706809b246994d1"},
+	{"restic", nil, "e19ece5aa03b18013e04bf6db1a11648e864ad6cbabb7f6d92f47b80791d11ee"},
+	{"restic", &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, Polynomial: 0x2c3a8d4ea6b0ab}, "3628eecbd557de20394d1c92991fc6b34c2ecd289c5c010e947b67c44af532a8"},
+	{"casync", nil, "f999260c863e67eb5f0544019746a64b00cf4495d6b066a4c08daed7bd4f3e75"},
+	{"casync", &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 32 << 10}, "7ce84aed03eed6599b01378eb7e64929a220b8193e4d4f62bd47e9b2c6382f7e"},
+	{"quickcdc", nil, "161c18ce8d6a9913d6a09a0110ba0b3f2dd5c6d384e777a1f05f5001ff7aa1fb"},
+	{"quickcdc", &chunkers.ChunkerOpts{MinSize: 64 << 10, NormalSize: 256 << 10, MaxSize: 1 << 20}, "9c66c745bed51dc2306695daea763e30f24eed360661e3f8183bb347937dff3a"},
+	{"rapidcdc", nil, "161c18ce8d6a9913d6a09a0110ba0b3f2dd5c6d384e777a1f05f5001ff7aa1fb"},
+	{"rapidcdc", &chunkers.ChunkerOpts{MinSize: 64 << 10, NormalSize: 256 << 10, MaxSize: 1 << 20}, "9c66c745bed51dc2306695daea763e30f24eed360661e3f8183bb347937dff3a"},
+	{"sourcecode", nil, "0c0cf82165c896aafa640398fd5ecdd6e658b4fbfa359cc07c8f29d6b1b2201d"},
+	{"sourcecode", &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 16 << 10, MaxSize: 128 << 10}, "f34ad2ab17e042ceae7674760af3e07a99ad02c706b4d48449745541aceff697"},
+	{"seqcdc", nil, "36679eec9743e336833a7712ddeadb9754e881497631824b5d6049424a8e3a51"},
+	{"seqcdc", &chunkers.ChunkerOpts{MinSize: 64 << 10, NormalSize: 256 << 10, MaxSize: 1 << 20}, "150a91c2bf7911fb0e0c7c0d139addd967041f3dc5dbee44db47264e414f01ea"},
+	{"pci", nil, "4861ce4be3e75c0134c7f483b5f19fa94537fc0c2168a2d08eae7e565421587f"},
+	{"pci", &chunkers.ChunkerOpts{MinSize: 64 << 10, NormalSize: 256 << 10, MaxSize: 1 << 20}, "826300ed79cfe35a434af9480cf9de586dbaf91f27dd0459cf0aa73086590d83"},
+	{"maxp", nil, "02332db90e639d07811b7e9b409ceb86d1ada705124b3a35ca5aef8c10cf86c7"},
+	{"maxp", &chunkers.ChunkerOpts{MinSize: 64 << 10, NormalSize: 256 << 10, MaxSize: 1 << 20, Window: 64 << 10}, "719654f92d2803894a87555df98320e63f8aa0eda046a22679f2857714beabe9"},
+	{"fixed", nil, "4388536fb9e1edf12303cbf0f222caffbe3fc7cf994c873544b19b964b95a12c"},
+	{"winnowing", nil, "166c9338cdb3dc61567909853fdb0141191465d5667d9a501d4138b29435440b"},
+	{"winnowing", &chunkers.ChunkerOpts{MinSize: 64 << 10, NormalSize: 256 << 10, MaxSize: 1 << 20, Window: 32 << 10}, "4476f253fb813a627d554a33e22987a4b9a47e6bfe39aeeedfdfef1e042a932c"},
+	{"rsync", nil, "2287480e8b3e9bd08286693961ada152e4c0e7f91a55fdec26cb74cc411d7182"},
+	{"rsync", &chunkers.ChunkerOpts{MinSize: 64 << 10, NormalSize: 256 << 10, MaxSize: 1 << 20, Window: 2 << 10}, "7f72253f33dbf67349f5af3a246b33e38da5ec1caafc528b4de9656112d7ffa9"},
+	// regression vectors, produced by this implementation once checked
+	// against rabinpoly's parameters by Test_LBFS_Matches_Reference
+	{"lbfs", nil, "583e5669fb296393c39e9c978490d09f24c7935d03d20c32739d31b9f81735be"},
+	{"lbfs", &chunkers.ChunkerOpts{MinSize: 64 << 10, NormalSize: 256 << 10, MaxSize: 1 << 20}, "a1579ec3237e7413e4464d0e2034e1a163f5e2e97fed334567645b337ca7559c"},
+	{"zstd-rsyncable", nil, "4b0e4d87e0a02de29fe69f1803c16403967af4fc16a965bb365c92b83b27156c"},
+	{"zstd-rsyncable", &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}, "fbd832b2423446a2a4940f67e36ba4665be5c9233891de1ad7182b5ca2c59dc0"},
+}
+
+func cutpointsDigest(t *testing.T, algorithm string, opts *chunkers.ChunkerOpts, data []byte) string {
+	chunker, err := chunkers.NewChunker(algorithm, bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	hasher := sha256.New()
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		return binary.Write(hasher, binary.LittleEndian, uint64(offset+length))
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+func Test_Determinism_Vectors(t *testing.T) {
+	var seed [32]byte
+	data := make([]byte, 4<<20)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	const regenerate = false
+	for _, vector := range determinismVectors {
+		digest := cutpointsDigest(t, vector.algorithm, vector.opts, data)
+		if regenerate {
+			fmt.Printf("%s %v: %s\n", vector.algorithm, vector.opts, digest)
+			continue
+		}
+		if digest != vector.expected {
+			t.Fatalf(`%s %v on %s: expected cutpoints digest %s, got %s`,
+				vector.algorithm, vector.opts, runtime.GOARCH, vector.expected, digest)
+		}
+	}
+}
+
+// Test_Determinism_386 re-runs the vectors as a 32-bit binary, which
+// amd64 hosts execute natively, to catch int-size dependent arithmetic.
+func Test_Determinism_386(t *testing.T) {
+	if testing.Short() || runtime.GOARCH != "amd64" || os.Getenv("GOARCH") == "386" {
+		t.Skip("needs an amd64 host")
+	}
+	gotool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not available")
+	}
+
+	cmd := exec.Command(gotool, "test", "-count=1", "-run", "^Test_Determinism_Vectors$", ".")
+	cmd.Env = append(os.Environ(), "GOARCH=386", "CGO_ENABLED=0")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("GOARCH=386 run failed: %s\n%s", err, output)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+import (
+	"bytes"
+	"crypto/sha256"
+)
+
+// SplitDigest behaves like Split but also hands the callback the digest
+// of every chunk, computed with ChunkerOpts.Hash. The digest, like the
+// chunk when buffers are borrowed, is only valid until the callback
+// returns. Chunks whose digest is among the ChunkerOpts.RecentDigests
+// last ones are flagged with FlagRepeat.
+func (chunker *Chunker) SplitDigest(callback func(offset, length uint64, chunk []byte, digest []byte) error) error {
+	newHash := chunker.options.Hash
+	if newHash == nil {
+		newHash = sha256.New
+	}
+	hasher := newHash()
+	digest := make([]byte, 0, hasher.Size())
+	recent := newRecentDigests(chunker.options.RecentDigests, hasher.Size())
+
+	return chunker.Split(func(offset, length uint64, chunk []byte) error {
+		hasher.Reset()
+		hasher.Write(chunk)
+		sum := hasher.Sum(digest[:0])
+		if recent.seen(sum) {
+			chunker.flags |= FlagRepeat
+		}
+		return callback(offset, length, chunk, sum)
+	})
+}
+
+// recentDigests is a ring of the last digests computed by SplitDigest,
+// small enough that a linear scan beats any index.
+type recentDigests struct {
+	digests []byte
+	size    int
+	next    int
+}
+
+func newRecentDigests(count, size int) *recentDigests {
+	return &recentDigests{digests: make([]byte, 0, count*size), size: size}
+}
+
+// reset forgets the digests seen so far.
+func (r *recentDigests) reset() {
+	r.digests = r.digests[:0]
+	r.next = 0
+}
+
+// seen reports whether digest is among the recent ones, remembering it
+// otherwise.
+func (r *recentDigests) seen(digest []byte) bool {
+	if cap(r.digests) == 0 {
+		return false
+	}
+	for i := 0; i < len(r.digests); i += r.size {
+		if bytes.Equal(r.digests[i:i+r.size], digest) {
+			return true
+		}
+	}
+	if len(r.digests) < cap(r.digests) {
+		r.digests = append(r.digests, digest...)
+		return false
+	}
+	copy(r.digests[r.next:], digest)
+	r.next = (r.next + r.size) % len(r.digests)
+	return false
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// EntropyInput identifies a random constant or seed that cutpoints depend
+// on, such as a Gear table or a Rabin polynomial, by the SHA-256 of its
+// little-endian encoding so that anyone holding the value can verify it.
+type EntropyInput struct {
+	Name   string
+	Digest [sha256.Size]byte
+}
+
+// NewEntropyInput returns the EntropyInput for value, which must be a
+// fixed-size value or array as accepted by encoding/binary.
+func NewEntropyInput(name string, value any) EntropyInput {
+	hasher := sha256.New()
+	if err := binary.Write(hasher, binary.LittleEndian, value); err != nil {
+		panic(err)
+	}
+	input := EntropyInput{Name: name}
+	hasher.Sum(input.Digest[:0])
+	return input
+}
+
+// EntropyImplementation is implemented by algorithms that depend on
+// random inputs, reporting the ones used with the given options.
+type EntropyImplementation interface {
+	ChunkerImplementation
+	Entropy(*ChunkerOpts) []EntropyInput
+}
+
+// Entropy reports every random input the chunker's cutpoints depend on,
+// after defaults are resolved, so that a backup can be re-chunked and
+// verified with the exact same inputs. Algorithms that only depend on
+// the data and the sizes report none.
+func (chunker *Chunker) Entropy() []EntropyInput {
+	implementation, ok := chunker.implementation.(EntropyImplementation)
+	if !ok {
+		return nil
+	}
+	return implementation.Entropy(chunker.options)
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math"
+	mathrand2 "math/rand/v2"
+)
+
+type Estimate struct {
+	// Chunks is the predicted number of chunks for the whole input,
+	// ChunksLow and ChunksHigh bound it with 95% confidence.
+	Chunks     float64
+	ChunksLow  float64
+	ChunksHigh float64
+
+	// DedupRatio is the fraction of sampled bytes belonging to chunks
+	// that occurred more than once across the samples.
+	DedupRatio float64
+
+	SampledBytes  int64
+	SampledChunks int
+}
+
+// EstimateChunks predicts the chunk count and dedup ratio of the first
+// size bytes of r by chunking only samples extents picked at random from
+// seed, one per equal slice of the input. Extents span 16 times MaxSize
+// and their first and last chunks are discarded, as they are cut by the
+// extent and not by the content. Inputs no larger than the sampled amount
+// are chunked entirely.
+func EstimateChunks(name string, opts *ChunkerOpts, r io.ReaderAt, size int64, samples int, seed uint64) (*Estimate, error) {
+	name, implementationAllocator, exists := lookup(name)
+	if !exists {
+		return nil, ErrUnknownAlgorithm
+	}
+	if opts == nil {
+		opts = defaultOptions(name, implementationAllocator)
+	}
+	if samples <= 0 {
+		return nil, errors.New("at least one sample is required")
+	}
+
+	extent := int64(16 * opts.MaxSize)
+	whole := size <= extent*int64(samples)
+	if whole {
+		extent = size
+		samples = 1
+	}
+
+	var lengths []int
+	var duplicates int64
+	digests := make(map[[32]byte]int)
+
+	rng := mathrand2.New(mathrand2.NewPCG(seed, 0))
+	buffer := make([]byte, extent)
+	estimate := &Estimate{}
+
+	for i := 0; i < samples; i++ {
+		// one extent per stratum so that samples never overlap, which
+		// would otherwise be counted as duplicates
+		offset := int64(0)
+		if !whole {
+			stratum := size / int64(samples)
+			offset = int64(i)*stratum + rng.Int64N(stratum-extent+1)
+		}
+		n, err := r.ReadAt(buffer, offset)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		// chunk data is only valid during the callback, keep digests
+		type sampled struct {
+			length int
+			digest [32]byte
+		}
+		var chunks []sampled
+		chunker, err := NewChunker(name, bytes.NewReader(buffer[:n]), opts)
+		if err != nil {
+			return nil, err
+		}
+		err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+			chunks = append(chunks, sampled{len(chunk), sha256.Sum256(chunk)})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !whole {
+			if len(chunks) < 3 {
+				continue
+			}
+			chunks = chunks[1 : len(chunks)-1]
+		}
+
+		for _, chunk := range chunks {
+			lengths = append(lengths, chunk.length)
+			estimate.SampledBytes += int64(chunk.length)
+			digests[chunk.digest]++
+			switch digests[chunk.digest] {
+			case 1:
+			case 2:
+				// the first occurrence retroactively becomes a duplicate
+				duplicates += 2 * int64(chunk.length)
+			default:
+				duplicates += int64(chunk.length)
+			}
+		}
+	}
+
+	estimate.SampledChunks = len(lengths)
+	if len(lengths) == 0 {
+		return estimate, nil
+	}
+	estimate.DedupRatio = float64(duplicates) / float64(estimate.SampledBytes)
+
+	if whole {
+		estimate.Chunks = float64(len(lengths))
+		estimate.ChunksLow = estimate.Chunks
+		estimate.ChunksHigh = estimate.Chunks
+		return estimate, nil
+	}
+
+	mean := float64(estimate.SampledBytes) / float64(len(lengths))
+	variance := 0.
+	for _, length := range lengths {
+		variance += (float64(length) - mean) * (float64(length) - mean)
+	}
+	if len(lengths) > 1 {
+		variance /= float64(len(lengths) - 1)
+	}
+	margin := 1.96 * math.Sqrt(variance/float64(len(lengths)))
+
+	estimate.Chunks = float64(size) / mean
+	estimate.ChunksHigh = float64(size) / math.Max(mean-margin, 1)
+	estimate.ChunksLow = float64(size) / (mean + margin)
+
+	return estimate, nil
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+import (
+	"runtime/debug"
+)
+
+const modulePath = "github.com/PlakarKorp/go-cdc-chunkers"
+
+// UnsafeImplementation is implemented by algorithms that read their input
+// through unsafe pointers rather than bounds-checked slices.
+type UnsafeImplementation interface {
+	ChunkerImplementation
+	Unsafe()
+}
+
+// FeatureSet describes the code paths of the library compiled into the
+// running binary.
+type FeatureSet struct {
+	// Version is the module version, see Version.
+	Version string
+
+	// Algorithms lists the registered algorithms, sorted.
+	Algorithms []string
+
+	// SIMD reports whether vectorized code paths are in use. Every
+	// algorithm is currently implemented in pure Go.
+	SIMD bool
+
+	// Unsafe lists the registered algorithms reading their input through
+	// unsafe pointers, sorted.
+	Unsafe []string
+}
+
+// Version returns the version of the module the running binary was built
+// with, "(devel)" when built from a local checkout, or an empty string if
+// the binary carries no build information.
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+		if dep.Replace != nil {
+			if dep.Replace.Version == "" {
+				// replaced by a local directory
+				return "(devel)"
+			}
+			return dep.Replace.Version
+		}
+		return dep.Version
+	}
+	return "(devel)"
+}
+
+// Features returns the version and code paths of the library, so that
+// applications can log exactly what produced their chunks and tell apart
+// deployments whose boundaries mismatch.
+func Features() FeatureSet {
+	features := FeatureSet{Version: Version(), Algorithms: List()}
+	for _, name := range features.Algorithms {
+		_, implementationAllocator, exists := lookup(name)
+		if !exists {
+			// unregistered meanwhile
+			continue
+		}
+		if _, ok := implementationAllocator().(UnsafeImplementation); ok {
+			features.Unsafe = append(features.Unsafe, name)
+		}
+	}
+	return features
+}
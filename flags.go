@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+// ChunkFlags qualifies how a cutpoint was chosen.
+type ChunkFlags uint8
+
+const (
+	// FlagForced marks a cut at MaxSize, as no content-defined cutpoint
+	// was found before it.
+	FlagForced ChunkFlags = 1 << iota
+
+	// FlagLowEntropy marks a cut placed by an algorithm's low-entropy
+	// detection, such as ultracdc on runs of repeated bytes.
+	FlagLowEntropy
+
+	// FlagHint marks a cut moved to one of ChunkerOpts.Hints.
+	FlagHint
+
+	// FlagRepeat marks a chunk identical to one of the last chunks of
+	// the stream, see ChunkerOpts.RecentDigests.
+	FlagRepeat
+
+	// FlagTimeCut marks a chunk emitted because ChunkerOpts.MaxLatency
+	// elapsed before a cutpoint was found.
+	FlagTimeCut
+)
+
+// FlaggingImplementation is implemented by algorithms that know why they
+// cut. Flags qualifies the cutpoint last returned by Algorithm. Chunkers
+// of other algorithms flag every cut at MaxSize as forced.
+type FlaggingImplementation interface {
+	ChunkerImplementation
+	Flags() ChunkFlags
+}
+
+// LevelingImplementation is implemented by algorithms that rank their
+// cutpoints, such as bupsplit whose fanout levels build trees of chunks.
+// Level qualifies the cutpoint last returned by Algorithm.
+type LevelingImplementation interface {
+	ChunkerImplementation
+	Level() int
+}
+
+// CutStats counts the chunks of a stream that did not end on a
+// content-defined cutpoint.
+type CutStats struct {
+	Chunks     uint64
+	Forced     uint64
+	LowEntropy uint64
+	TimeCuts   uint64
+}
+
+// ForcedRatio returns the fraction of chunks cut at MaxSize so far. On
+// data that is neither incompressible padding nor repetitive, a high
+// ratio means MaxSize is too close to NormalSize.
+func (s CutStats) ForcedRatio() float64 {
+	if s.Chunks == 0 {
+		return 0
+	}
+	return float64(s.Forced) / float64(s.Chunks)
+}
+
+// Flags returns the flags of the last chunk returned.
+func (chunker *Chunker) Flags() ChunkFlags {
+	return chunker.flags
+}
+
+// Level returns the level of the last chunk returned, zero unless the
+// algorithm ranks its cutpoints and the chunk ended on one of them.
+func (chunker *Chunker) Level() int {
+	return chunker.level
+}
+
+// Tags returns the tags ChunkerOpts.Tagger attached to the last chunk
+// returned, nil if there is no tagger.
+func (chunker *Chunker) Tags() map[string]string {
+	return chunker.tags
+}
+
+// CutStats returns the running totals of the chunks returned so far.
+func (chunker *Chunker) CutStats() CutStats {
+	return chunker.cuts
+}
+
+// flag records the flags of the chunk ending at cutpoint, those set by
+// the chunker itself taking precedence over the algorithm's.
+func (chunker *Chunker) flag(data []byte, cutpoint int, flags ChunkFlags, merged bool) {
+	switch {
+	case flags != 0:
+	case merged:
+		// the algorithm's cut was vetoed, its flags describe another chunk
+	case chunker.flagging != nil:
+		flags = chunker.flagging.Flags()
+	case cutpoint == chunker.maxSize && len(data) == chunker.maxSize:
+		flags = FlagForced
+	}
+
+	chunker.flags = flags
+	chunker.cuts.Chunks++
+	if flags&FlagForced != 0 {
+		chunker.cuts.Forced++
+	}
+	if flags&FlagLowEntropy != 0 {
+		chunker.cuts.LowEntropy++
+	}
+	if flags&FlagTimeCut != 0 {
+		chunker.cuts.TimeCuts++
+	}
+}
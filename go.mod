@@ -1,11 +1,3 @@
 module github.com/PlakarKorp/go-cdc-chunkers
 
 go 1.23
-
-require (
-	codeberg.org/mhofmann/fastcdc v1.0.0
-	github.com/askeladdk/fastcdc v0.0.2
-	github.com/jotfs/fastcdc-go v0.2.0
-	github.com/restic/chunker v0.4.0
-	github.com/tigerwill90/fastcdc v1.2.2
-)
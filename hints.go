@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var ErrHint = errors.New("malformed hint")
+
+// ReadHints parses a hints file as produced by format-specific analyzers:
+// one decimal stream offset per line, blank lines and lines starting with
+// '#' being ignored. The offsets are returned sorted and deduplicated,
+// ready for ChunkerOpts.Hints.
+func ReadHints(r io.Reader) ([]int64, error) {
+	var hints []int64
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		hint, err := strconv.ParseInt(text, 10, 64)
+		if err != nil || hint < 0 {
+			return nil, fmt.Errorf("%w at line %d: %q", ErrHint, line, text)
+		}
+		hints = append(hints, hint)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	slices.Sort(hints)
+	return slices.Compact(hints), nil
+}
+
+// BoundaryScanner proposes hints from the content of a stream, see
+// ChunkerOpts.Scanner. Each chunker has its own.
+type BoundaryScanner interface {
+	// Scan is handed every byte of the stream once and in order, data
+	// starting at stream offset, and appends to hints the offsets where
+	// chunks should start that it found, in increasing order and past
+	// those returned by previous calls. Hints may lie beyond data, such
+	// as the end of an archive member whose size was read from its
+	// header.
+	Scan(hints []int64, offset int64, data []byte) []int64
+}
+
+// snap moves cutpoint to the closest hint within HintTolerance, provided
+// the chunk keeps at least MinSize bytes and does not extend past the n
+// bytes available. It reports whether the cutpoint moved.
+func (chunker *Chunker) snap(cutpoint, n int) (int, bool) {
+	hints := chunker.options.Hints
+
+	// hints are sorted, those behind the current chunk are done with
+	for chunker.hint < len(hints) && hints[chunker.hint] <= chunker.position {
+		chunker.hint++
+	}
+	for len(chunker.proposed) != 0 && chunker.proposed[0] <= chunker.position {
+		chunker.proposed = chunker.proposed[1:]
+	}
+
+	best, snapped := chunker.closest(hints[chunker.hint:], cutpoint, n, cutpoint, false)
+	return chunker.closest(chunker.proposed, cutpoint, n, best, snapped)
+}
+
+// closest returns the closer to cutpoint of best, if snapped, and of the
+// hints within reach.
+func (chunker *Chunker) closest(hints []int64, cutpoint, n, best int, snapped bool) (int, bool) {
+	tolerance := int64(chunker.options.HintTolerance)
+	target := chunker.position + int64(cutpoint)
+	for _, hint := range hints {
+		if hint > target+tolerance {
+			break
+		}
+		if hint < target-tolerance {
+			continue
+		}
+		relative := int(hint - chunker.position)
+		if relative < chunker.minSize || relative > n {
+			continue
+		}
+		if !snapped || abs(hint-target) < abs(int64(best)+chunker.position-target) {
+			best, snapped = relative, true
+		}
+	}
+	return best, snapped
+}
+
+func abs(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,223 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+// Package tar proposes chunk boundaries at the members of tar archives,
+// so that a file stored in different archives, or in successive versions
+// of one, yields the same chunks.
+package tar
+
+import (
+	"bytes"
+	"strconv"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+const blockSize = 512
+
+// maxPaxSize bounds the pax extended headers read for a size override,
+// larger ones end the scan.
+const maxPaxSize = 64 * 1024
+
+// Scanner follows the headers of a tar stream, ustar, GNU or pax, and
+// proposes as hints the start of the content of every member, past its
+// headers: cutting there rather than before the headers keeps the
+// metadata of a file, its mtime for one, out of the chunks of its
+// content. It stops proposing hints at the end of the archive or at the
+// first header it cannot follow, such as a corrupted one, the stream
+// being chunked as usual from there on.
+type Scanner struct {
+	started bool
+	done    bool
+
+	// next is the offset of the next byte to gather into block, once
+	// want bytes are gathered the header or pax records are processed
+	next  int64
+	block []byte
+	want  int
+	pax   bool
+
+	// size overrides the size of the next header if positive, as read
+	// from pax records
+	size int64
+}
+
+// NewScanner returns a scanner for ChunkerOpts.Scanner.
+func NewScanner() chunkers.BoundaryScanner {
+	return &Scanner{want: blockSize, size: -1}
+}
+
+func (s *Scanner) Scan(hints []int64, offset int64, data []byte) []int64 {
+	if !s.started {
+		// the stream must start with a header, even if chunking resumes
+		// at an anchor
+		s.started = true
+		s.next = offset
+	}
+
+	for !s.done {
+		if len(s.block) < s.want {
+			if offset+int64(len(data)) <= s.next {
+				return hints
+			}
+			if offset < s.next {
+				data = data[s.next-offset:]
+				offset = s.next
+			}
+			n := min(s.want-len(s.block), len(data))
+			s.block = append(s.block, data[:n]...)
+			data = data[n:]
+			offset += int64(n)
+			s.next += int64(n)
+			if len(s.block) < s.want {
+				return hints
+			}
+		}
+
+		if s.pax {
+			s.records()
+		} else {
+			hints = s.header(hints)
+		}
+		s.block = s.block[:0]
+	}
+	return hints
+}
+
+// header processes the header in block, which ends at next.
+func (s *Scanner) header(hints []int64) []int64 {
+	header := s.block
+	if !checksum(header) {
+		// also the zero blocks ending the archive
+		s.done = true
+		return hints
+	}
+	size, ok := number(header[124:136])
+	if !ok || size < 0 {
+		s.done = true
+		return hints
+	}
+
+	content := s.next
+	switch header[156] {
+	case 'x':
+		// pax records may override the size of the next header
+		if size > maxPaxSize {
+			s.done = true
+			return hints
+		}
+		s.pax = true
+		s.want = int(size)
+		return hints
+	case 'g', 'L', 'K':
+		s.next = content + padded(size)
+		return hints
+	case 'S':
+		if header[482] != 0 {
+			// extended sparse headers are not followed
+			s.done = true
+			return hints
+		}
+	case '1', '2', '3', '4', '5', '6':
+		// links, devices, directories and fifos carry no content
+		size = 0
+	}
+	if s.size >= 0 {
+		size = s.size
+	}
+
+	if size != 0 {
+		hints = append(hints, content)
+	}
+	s.size = -1
+	s.next = content + padded(size)
+	return hints
+}
+
+// records processes the pax records in block, which ends at next.
+func (s *Scanner) records() {
+	size := int64(len(s.block))
+	for records := s.block; len(records) != 0; {
+		space := bytes.IndexByte(records, ' ')
+		if space <= 0 {
+			break
+		}
+		length, err := strconv.Atoi(string(records[:space]))
+		if err != nil || length <= space+1 || length > len(records) || records[length-1] != '\n' {
+			break
+		}
+		key, value, found := bytes.Cut(records[space+1:length-1], []byte{'='})
+		if found && string(key) == "size" {
+			if override, err := strconv.ParseInt(string(value), 10, 64); err == nil && override >= 0 {
+				s.size = override
+			}
+		}
+		records = records[length:]
+	}
+
+	s.pax = false
+	s.want = blockSize
+	s.next += padded(size) - size
+}
+
+// checksum reports whether header is a valid header, the sum of its bytes
+// with the checksum field counted as spaces matching that field, either
+// unsigned or signed as some historic implementations did.
+func checksum(header []byte) bool {
+	recorded, ok := number(header[148:156])
+	if !ok {
+		return false
+	}
+	var unsigned, signed int64
+	for i, b := range header {
+		if i >= 148 && i < 156 {
+			b = ' '
+		}
+		unsigned += int64(b)
+		signed += int64(int8(b))
+	}
+	return recorded == unsigned || recorded == signed
+}
+
+// number parses a numeric header field, octal or GNU base-256.
+func number(field []byte) (int64, bool) {
+	if len(field) != 0 && field[0]&0x80 != 0 {
+		if field[0]&0x40 != 0 {
+			// negative
+			return 0, false
+		}
+		value := int64(field[0] & 0x3f)
+		for _, b := range field[1:] {
+			if value > (1<<63-1)>>8 {
+				return 0, false
+			}
+			value = value<<8 | int64(b)
+		}
+		return value, true
+	}
+
+	field = bytes.Trim(field, " \x00")
+	if len(field) == 0 {
+		return 0, true
+	}
+	value, err := strconv.ParseInt(string(field), 8, 64)
+	return value, err == nil
+}
+
+// padded returns size rounded up to a whole number of blocks.
+func padded(size int64) int64 {
+	return (size + blockSize - 1) &^ (blockSize - 1)
+}
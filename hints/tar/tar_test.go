@@ -0,0 +1,118 @@
+package tar
+
+import (
+	"archive/tar"
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// archive returns a tar archive and the hints expected for its members.
+func archive(t *testing.T, format tar.Format) ([]byte, []int64) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	var expected []int64
+	add := func(hdr *tar.Header, content []byte) {
+		if err := tw.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		hdr.Format = format
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if len(content) != 0 {
+			expected = append(expected, int64(buf.Len()))
+		}
+		if _, err := tw.Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	add(&tar.Header{Name: "dir/", Typeflag: tar.TypeDir, Mode: 0755}, nil)
+	add(&tar.Header{Name: "dir/a", Typeflag: tar.TypeReg, Mode: 0644, Size: 1000}, bytes.Repeat([]byte{'a'}, 1000))
+	add(&tar.Header{Name: "dir/empty", Typeflag: tar.TypeReg, Mode: 0644}, nil)
+	add(&tar.Header{Name: "dir/link", Typeflag: tar.TypeSymlink, Linkname: "a"}, nil)
+	long := "dir/" + strings.Repeat("long/", 40) + "b"
+	add(&tar.Header{Name: long, Typeflag: tar.TypeReg, Mode: 0644, Size: 512}, bytes.Repeat([]byte{'b'}, 512))
+	c := &tar.Header{Name: "dir/c", Typeflag: tar.TypeReg, Mode: 0644, Size: 3}
+	if format == tar.FormatPAX {
+		// an extended header precedes the member
+		c.PAXRecords = map[string]string{"comment": "extended"}
+	}
+	add(c, []byte("ccc"))
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes(), expected
+}
+
+func scan(data []byte, offset int64, step int) []int64 {
+	scanner := NewScanner()
+	var hints []int64
+	for i := 0; i < len(data); i += step {
+		hints = scanner.Scan(hints, offset+int64(i), data[i:min(i+step, len(data))])
+	}
+	return hints
+}
+
+func TestScanner(t *testing.T) {
+	for _, format := range []tar.Format{tar.FormatPAX, tar.FormatGNU} {
+		data, expected := archive(t, format)
+		for _, step := range []int{1, 100, 512, 4096, len(data)} {
+			if hints := scan(data, 0, step); !slices.Equal(hints, expected) {
+				t.Fatalf(`%s by %d bytes: hints %v, expected %v`, format, step, hints, expected)
+			}
+		}
+
+		// offsets count from the first byte scanned
+		hints := scan(data, 1000, 333)
+		for i := range hints {
+			hints[i] -= 1000
+		}
+		if !slices.Equal(hints, expected) {
+			t.Fatalf(`%s at offset 1000: hints %v, expected %v`, format, hints, expected)
+		}
+	}
+}
+
+func TestScannerStops(t *testing.T) {
+	data, expected := archive(t, tar.FormatPAX)
+
+	// corrupting the checksum of the last member stops the scan there
+	corrupted := bytes.Clone(data)
+	corrupted[expected[2]-512+148]++
+	if hints := scan(corrupted, 0, 700); !slices.Equal(hints, expected[:2]) {
+		t.Fatalf(`hints %v, expected %v`, hints, expected[:2])
+	}
+
+	// data following the archive is not scanned
+	trailing := append(bytes.Clone(data), data...)
+	if hints := scan(trailing, 0, 4096); !slices.Equal(hints, expected) {
+		t.Fatalf(`hints %v, expected %v`, hints, expected)
+	}
+
+	if hints := scan(bytes.Repeat([]byte{'x'}, 8192), 0, 1024); len(hints) != 0 {
+		t.Fatalf(`hints %v in a stream that is not a tar archive`, hints)
+	}
+}
+
+func TestNumber(t *testing.T) {
+	for _, test := range []struct {
+		field string
+		value int64
+		ok    bool
+	}{
+		{"00000001750\x00", 1000, true},
+		{"     1750 \x00", 1000, true},
+		{"\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00", 0, true},
+		{"\x80\x00\x00\x00\x00\x00\x00\x02\x00\x00\x00\x00", 1 << 33, true},
+		{"\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff", 0, false},
+		{"0000000175x\x00", 0, false},
+	} {
+		value, ok := number([]byte(test.field))
+		if value != test.value || ok != test.ok {
+			t.Fatalf(`number(%q) = %d, %v, expected %d, %v`, test.field, value, ok, test.value, test.ok)
+		}
+	}
+}
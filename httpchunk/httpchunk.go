@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package httpchunk
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+var ErrDigestMismatch = errors.New("request body does not match its digest trailer")
+var ErrDigestMissing = errors.New("announced digest trailer was not received")
+
+// ChunkBody chunks the body of r as it is received, calling callback for
+// each chunk before the rest of the body has arrived. If the client
+// announced a Content-Digest (RFC 9530) or Digest (RFC 3230) trailer, the
+// SHA-256 of the body is verified against it once the body is consumed
+// and ErrDigestMismatch is returned if they differ. Trailers using other
+// digest algorithms are ignored.
+func ChunkBody(r *http.Request, algorithm string, opts *chunkers.ChunkerOpts, callback func(offset, length uint64, chunk []byte) error) error {
+	var hasher hash.Hash
+	var body io.Reader = r.Body
+	if digestTrailer(r.Trailer) != "" {
+		hasher = sha256.New()
+		body = io.TeeReader(r.Body, hasher)
+	}
+
+	chunker, err := chunkers.NewChunker(algorithm, body, opts)
+	if err != nil {
+		return err
+	}
+	if err := chunker.Split(callback); err != nil {
+		return err
+	}
+	if hasher == nil {
+		return nil
+	}
+
+	// trailers are only populated once the body hit EOF
+	expected, ok := parseDigest(r.Trailer.Get(digestTrailer(r.Trailer)))
+	if !ok {
+		return ErrDigestMissing
+	}
+	if !bytes.Equal(expected, hasher.Sum(nil)) {
+		return ErrDigestMismatch
+	}
+	return nil
+}
+
+func digestTrailer(trailer http.Header) string {
+	for _, name := range []string{"Content-Digest", "Digest"} {
+		if _, exists := trailer[name]; exists {
+			return name
+		}
+	}
+	return ""
+}
+
+// parseDigest extracts the sha-256 entry of a digest field, accepting
+// both "sha-256=:base64:" and the legacy "SHA-256=base64" forms.
+func parseDigest(field string) ([]byte, bool) {
+	for _, entry := range strings.Split(field, ",") {
+		algorithm, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found || !strings.EqualFold(algorithm, "sha-256") {
+			continue
+		}
+		digest, err := base64.StdEncoding.DecodeString(strings.Trim(value, ":"))
+		if err != nil || len(digest) != sha256.Size {
+			return nil, false
+		}
+		return digest, true
+	}
+	return nil, false
+}
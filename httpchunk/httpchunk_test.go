@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package httpchunk
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	mathrand2 "math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+)
+
+func upload(t *testing.T, data []byte, trailer string) (int, uint64) {
+	var total uint64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		total = 0
+		err := ChunkBody(r, "fastcdc", nil, func(offset, length uint64, chunk []byte) error {
+			total += length
+			return nil
+		})
+		switch err {
+		case nil:
+			w.WriteHeader(http.StatusOK)
+		case ErrDigestMismatch:
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	// a pipe forces a chunked transfer encoding, which trailers require
+	pr, pw := io.Pipe()
+	go func() {
+		pw.Write(data)
+		pw.Close()
+	}()
+
+	req, err := http.NewRequest(http.MethodPut, server.URL, pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trailer != "" {
+		req.Trailer = http.Header{"Content-Digest": {trailer}}
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	return res.StatusCode, total
+}
+
+func Test_ChunkBody(t *testing.T) {
+	var seed [32]byte
+	data := make([]byte, 1<<20)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	sum := sha256.Sum256(data)
+	good := "sha-256=:" + base64.StdEncoding.EncodeToString(sum[:]) + ":"
+	bad := "sha-256=:" + base64.StdEncoding.EncodeToString(make([]byte, sha256.Size)) + ":"
+
+	for _, test := range []struct {
+		trailer string
+		status  int
+	}{
+		{"", http.StatusOK},
+		{good, http.StatusOK},
+		{bad, http.StatusBadRequest},
+	} {
+		status, total := upload(t, data, test.trailer)
+		if status != test.status {
+			t.Fatalf(`expected status %d, got %d`, test.status, status)
+		}
+		if total != uint64(len(data)) {
+			t.Fatalf(`expected %d bytes chunked, got %d`, len(data), total)
+		}
+	}
+}
+
+func Test_parseDigest(t *testing.T) {
+	sum := sha256.Sum256([]byte("hello"))
+	encoded := base64.StdEncoding.EncodeToString(sum[:])
+
+	for _, field := range []string{
+		"sha-256=:" + encoded + ":",
+		"SHA-256=" + encoded,
+		"md5=:AAAA:, sha-256=:" + encoded + ":",
+	} {
+		digest, ok := parseDigest(field)
+		if !ok || !bytes.Equal(digest, sum[:]) {
+			t.Fatalf(`failed to parse %q`, field)
+		}
+	}
+	if _, ok := parseDigest("md5=:AAAA:"); ok {
+		t.Fatalf(`parsed a field without sha-256 entry`)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// ErrTimeCutIdentity is returned by NewChunker when both StreamIdentity and
+// MaxLatency are set: time cuts depend on when data arrives, so the same
+// stream would not yield the same identity twice.
+var ErrTimeCutIdentity = errors.New("stream identity cannot be computed with time cuts")
+
+// IdentityExtension is implemented by extensions, see
+// ChunkerOpts.Extension, to encode the options they carry into stream
+// identities and chunker states. Identity appends to dst an encoding
+// that is equal for equal options whichever process builds them, such as
+// the contents of a table rather than its address. Extensions that do not
+// implement it are encoded as JSON.
+type IdentityExtension interface {
+	Identity(dst []byte) []byte
+}
+
+// newIdentity returns the hash accumulating a stream identity, seeded with
+// the algorithm and every option that influences cutpoints so that the
+// same bytes chunked differently never share an identity.
+func newIdentity(algorithm string, opts *ChunkerOpts) hash.Hash {
+	identity := sha256.New()
+	identity.Write([]byte("go-cdc-chunkers stream identity v1\x00"))
+	identity.Write([]byte(algorithm))
+	identity.Write([]byte{0})
+	for _, value := range []int{opts.MinSize, opts.NormalSize, opts.MaxSize, opts.MinTailSize, opts.Window, opts.NormalizationLevel} {
+		binary.Write(identity, binary.LittleEndian, uint64(value))
+	}
+	binary.Write(identity, binary.LittleEndian, opts.Polynomial)
+	if len(opts.Hints) != 0 || opts.Scanner != nil {
+		binary.Write(identity, binary.LittleEndian, uint64(opts.HintTolerance))
+		binary.Write(identity, binary.LittleEndian, opts.Hints)
+	}
+	if opts.Scanner != nil {
+		fmt.Fprintf(identity, "%T", opts.Scanner())
+	}
+	if opts.Extension != nil {
+		fmt.Fprintf(identity, "%T\x00", opts.Extension)
+		if ext, ok := opts.Extension.(IdentityExtension); ok {
+			identity.Write(ext.Identity(nil))
+		} else {
+			// unlike formatting, encoding follows pointers rather than
+			// writing their addresses
+			encoded, _ := json.Marshal(opts.Extension)
+			identity.Write(encoded)
+		}
+	}
+	if len(opts.Key) != 0 {
+		// the key itself must not be recoverable from the identity
+		derived := DeriveKey(opts.Key, "stream identity")
+		identity.Write(derived[:])
+	}
+	return identity
+}
+
+// StreamIdentity returns the SHA-256 of the ordered SHA-256 digests of the
+// chunks produced so far, bound to the algorithm and options. Once the
+// stream is fully consumed, through Next, Copy or Split, two parties can
+// compare identities to tell whether they hold the same stream chunked
+// the same way without exchanging data. It returns nil unless
+// ChunkerOpts.StreamIdentity is set.
+func (chunker *Chunker) StreamIdentity() []byte {
+	if chunker.identity == nil {
+		return nil
+	}
+	return chunker.identity.Sum(nil)
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+)
+
+// ErrUnkeyed is returned by NewChunker when ChunkerOpts.Key is set for an
+// algorithm that has no constants to derive from it, rather than chunking
+// with predictable cutpoints. It matches ErrInvalidOptions.
+var ErrUnkeyed = fmt.Errorf("%w: algorithm cannot be keyed", ErrInvalidOptions)
+
+// KeyedImplementation is implemented by algorithms whose constants, such
+// as a Gear table or a Rabin polynomial, are derived from ChunkerOpts.Key
+// when it is set.
+type KeyedImplementation interface {
+	ChunkerImplementation
+	Keyed()
+}
+
+// DeriveKey returns the key to derive the constant named label from, the
+// HMAC-SHA256 of label under key, so that constants derived from the same
+// key are independent of each other.
+func DeriveKey(key []byte, label string) [sha256.Size]byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(label))
+	var derived [sha256.Size]byte
+	mac.Sum(derived[:0])
+	return derived
+}
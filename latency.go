@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned by Next once Close was called on a chunker with
+// MaxLatency set.
+var ErrClosed = errors.New("chunker closed")
+
+// errLatency is returned by latencyReader when MaxLatency elapsed without
+// new data, it never reaches callers.
+var errLatency = errors.New("max latency reached")
+
+type readResult struct {
+	data []byte
+	err  error
+}
+
+// latencyReader reads from a live stream in the background so that reads
+// can give up once MaxLatency elapsed since the first byte of the current
+// chunk was received. The background goroutine exits once the stream
+// returns an error, such as when the writing end of an io.Pipe is closed,
+// or once the reader is closed and its pending Read returned.
+type latencyReader struct {
+	latency time.Duration
+	results chan readResult
+	ack     chan struct{}
+	done    chan struct{}
+	close   sync.Once
+	timer   *time.Timer
+
+	pending readResult
+	holding bool
+	start   time.Time
+
+	// err is the terminal error of the stream, returned by every Read
+	// once the data before it was consumed
+	err error
+}
+
+func newLatencyReader(r io.Reader, latency time.Duration, size int) *latencyReader {
+	lr := &latencyReader{
+		latency: latency,
+		results: make(chan readResult, 1),
+		ack:     make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		timer:   time.NewTimer(latency),
+	}
+	lr.timer.Stop()
+
+	go func() {
+		buf := make([]byte, size)
+		for {
+			n, err := r.Read(buf)
+			select {
+			case lr.results <- readResult{buf[:n], err}:
+			case <-lr.done:
+				return
+			}
+			if err != nil {
+				return
+			}
+			// buf is handed out until the chunker is done with it
+			select {
+			case <-lr.ack:
+			case <-lr.done:
+				return
+			}
+		}
+	}()
+	return lr
+}
+
+// Close stops the background goroutine and fails pending and later reads
+// with ErrClosed. It may be called from any goroutine.
+func (lr *latencyReader) Close() error {
+	lr.close.Do(func() {
+		close(lr.done)
+	})
+	return nil
+}
+
+// restart starts a new chunk, which already holds data if buffered.
+func (lr *latencyReader) restart(buffered bool) {
+	if buffered {
+		lr.start = time.Now()
+	} else {
+		lr.start = time.Time{}
+	}
+}
+
+func (lr *latencyReader) Read(p []byte) (int, error) {
+	if lr.err != nil {
+		return 0, lr.err
+	}
+
+	if !lr.holding {
+		var timeout <-chan time.Time
+		if !lr.start.IsZero() {
+			wait := time.Until(lr.start.Add(lr.latency))
+			if wait <= 0 {
+				return 0, errLatency
+			}
+			lr.timer.Reset(wait)
+			defer lr.timer.Stop()
+			timeout = lr.timer.C
+		}
+		select {
+		case lr.pending = <-lr.results:
+		case <-timeout:
+			return 0, errLatency
+		case <-lr.done:
+			lr.err = ErrClosed
+			return 0, lr.err
+		}
+		lr.holding = true
+	}
+
+	n := copy(p, lr.pending.data)
+	lr.pending.data = lr.pending.data[n:]
+	if n != 0 && lr.start.IsZero() {
+		lr.start = time.Now()
+	}
+	if len(lr.pending.data) != 0 {
+		return n, nil
+	}
+
+	lr.holding = false
+	if lr.pending.err != nil {
+		lr.err = lr.pending.err
+		return n, lr.err
+	}
+	lr.ack <- struct{}{}
+	return n, nil
+}
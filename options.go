@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+// Option sets a field of ChunkerOpts, see NewOptions. Algorithms provide
+// their own to set their Extension, such as ultracdc.WithStride.
+type Option func(*ChunkerOpts)
+
+func WithMinSize(size int) Option {
+	return func(opts *ChunkerOpts) { opts.MinSize = size }
+}
+
+func WithMaxSize(size int) Option {
+	return func(opts *ChunkerOpts) { opts.MaxSize = size }
+}
+
+func WithNormalSize(size int) Option {
+	return func(opts *ChunkerOpts) { opts.NormalSize = size }
+}
+
+// WithNormalization sets NormalizationLevel.
+//
+// Deprecated: use the option of the algorithm, such as
+// fastcdc.WithNormalization.
+func WithNormalization(level int) Option {
+	return func(opts *ChunkerOpts) { opts.NormalizationLevel = level }
+}
+
+// WithPolynomial sets Polynomial.
+//
+// Deprecated: use the option of the algorithm, such as
+// restic.WithPolynomial.
+func WithPolynomial(polynomial uint64) Option {
+	return func(opts *ChunkerOpts) { opts.Polynomial = polynomial }
+}
+
+func WithKey(key []byte) Option {
+	return func(opts *ChunkerOpts) { opts.Key = key }
+}
+
+func WithExtension(extension any) Option {
+	return func(opts *ChunkerOpts) { opts.Extension = extension }
+}
+
+// NewOptions returns the options in effect for an algorithm when none are
+// passed, as set by SetDefaultOptions, with options applied in order on
+// top, so that callers only spell out the fields they change. The result
+// is validated by the algorithm.
+func NewOptions(algorithm string, options ...Option) (*ChunkerOpts, error) {
+	algorithm, implementationAllocator, exists := lookup(algorithm)
+	if !exists {
+		return nil, ErrUnknownAlgorithm
+	}
+	opts := defaultOptions(algorithm, implementationAllocator)
+	for _, option := range options {
+		option(opts)
+	}
+
+	implementation := implementationAllocator()
+	if err := validate(implementation, opts); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package chunkers
+
+import (
+	"errors"
+	"io"
+)
+
+var ErrQueueDepth = errors.New("queue depth must be at least 1")
+
+// queued is a chunk waiting to be written by CopyQueue.
+type queued struct {
+	offset uint64
+	data   []byte
+}
+
+// CopyQueue behaves like Copy but writes to dst from another goroutine, so
+// that chunking overlaps with writes to a slow writer. Chunks are copied to
+// one of depth buffers of MaxSize bytes and queued, chunking blocks while
+// all of them are queued, and dst sees exactly the Write calls Copy would
+// make, in the same order. When a Write fails, chunking stops and its
+// error is returned. The writing goroutine has returned once CopyQueue
+// does, so dst is not used afterwards.
+func (chunker *Chunker) CopyQueue(dst io.Writer, depth int) (int64, error) {
+	if depth < 1 {
+		return 0, ErrQueueDepth
+	}
+
+	free := make(chan []byte, depth)
+	for i := 0; i < depth; i++ {
+		free <- make([]byte, 0, chunker.maxSize)
+	}
+	queue := make(chan queued, depth)
+	failed := make(chan struct{})
+	done := make(chan error, 1)
+
+	go func() {
+		var werr error
+		for chunk := range queue {
+			if werr == nil {
+				if _, werr = write(dst, chunk.offset, chunk.data); werr != nil {
+					close(failed)
+				}
+			}
+			// free holds every buffer, this never blocks
+			free <- chunk.data[:0]
+		}
+		done <- werr
+	}()
+
+	nbytes := int64(0)
+	var err error
+	for {
+		var chunk []byte
+		chunk, err = chunker.next()
+		if err != nil && err != io.EOF {
+			break
+		}
+
+		if len(chunk) != 0 {
+			var buf []byte
+			select {
+			case buf = <-free:
+			case <-failed:
+			}
+			if buf == nil {
+				break
+			}
+			queue <- queued{offset: uint64(chunker.position), data: append(buf, chunk...)}
+		}
+		if err == io.EOF {
+			break
+		}
+
+		nbytes += int64(len(chunk))
+	}
+	close(queue)
+
+	if werr := <-done; werr != nil {
+		return nbytes, werr
+	}
+	return nbytes, err
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	mathrand2 "math/rand/v2"
+	"runtime"
+	"time"
+)
+
+// selfBenchSize is the minimum amount of synthetic data chunked per pass.
+const selfBenchSize = 16 << 20
+
+type BenchResult struct {
+	Algorithm string        `json:"algorithm"`
+	Corpus    string        `json:"corpus"`
+	Passes    int           `json:"passes"`
+	Bytes     int64         `json:"bytes"`
+	Chunks    int64         `json:"chunks"`
+	Elapsed   time.Duration `json:"elapsed_ns"`
+
+	GBps         float64 `json:"gbps"`
+	ChunksPerSec float64 `json:"chunks_per_sec"`
+
+	// AllocsPerPass is the number of heap allocations made chunking the
+	// whole corpus once, chunker setup included. Chunks are borrowed from
+	// the chunker as with ChunkerOpts.BorrowBuffers, without which Next
+	// allocates an owned copy per chunk.
+	AllocsPerPass float64 `json:"allocs_per_pass"`
+
+	// DedupRatio is the fraction of the corpus made of chunks already
+	// seen earlier in the corpus.
+	DedupRatio float64 `json:"dedup_ratio"`
+}
+
+// SelfBench measures the throughput of an algorithm on this machine by
+// chunking deterministic pseudo-random data for at least duration. At
+// least one full pass over the data is always performed, so a zero
+// duration is valid and yields a quick, coarse measurement.
+func SelfBench(name string, opts *ChunkerOpts, duration time.Duration) (*BenchResult, error) {
+	name, implementationAllocator, exists := lookup(name)
+	if !exists {
+		return nil, ErrUnknownAlgorithm
+	}
+	if opts == nil {
+		opts = defaultOptions(name, implementationAllocator)
+	}
+
+	size := selfBenchSize
+	if size < 8*opts.MaxSize {
+		size = 8 * opts.MaxSize
+	}
+
+	var seed [32]byte
+	data := make([]byte, size)
+	mathrand2.NewChaCha8(seed).Read(data)
+
+	return BenchCorpus(name, opts, "synthetic", data, duration)
+}
+
+// BenchCorpus is SelfBench over caller-provided data, labelled corpus in
+// the result so that runs over several corpora can be told apart.
+func BenchCorpus(name string, opts *ChunkerOpts, corpus string, data []byte, duration time.Duration) (*BenchResult, error) {
+	if _, _, exists := lookup(name); !exists {
+		return nil, ErrUnknownAlgorithm
+	}
+
+	result := &BenchResult{Algorithm: name, Corpus: corpus}
+	rd := bytes.NewReader(data)
+
+	// hashing would skew the timings, measure dedup in its own pass
+	chunker, err := NewChunker(name, rd, opts)
+	if err != nil {
+		return nil, err
+	}
+	duplicates := 0
+	digests := make(map[[32]byte]struct{})
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		digest := sha256.Sum256(chunk)
+		if _, exists := digests[digest]; exists {
+			duplicates += len(chunk)
+		}
+		digests[digest] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 0 {
+		result.DedupRatio = float64(duplicates) / float64(len(data))
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	start := time.Now()
+	for result.Passes == 0 || time.Since(start) < duration {
+		rd.Reset(data)
+		chunker, err := NewChunker(name, rd, opts)
+		if err != nil {
+			return nil, err
+		}
+		for {
+			chunk, err := chunker.next()
+			if err != nil && err != io.EOF {
+				return nil, err
+			}
+			if len(chunk) != 0 {
+				result.Chunks++
+				result.Bytes += int64(len(chunk))
+			}
+			if err == io.EOF {
+				break
+			}
+		}
+		result.Passes++
+	}
+	result.Elapsed = time.Since(start)
+
+	runtime.ReadMemStats(&after)
+	result.AllocsPerPass = float64(after.Mallocs-before.Mallocs) / float64(result.Passes)
+
+	seconds := result.Elapsed.Seconds()
+	result.GBps = float64(result.Bytes) / seconds / 1e9
+	result.ChunksPerSec = float64(result.Chunks) / seconds
+
+	return result, nil
+}
+
+// WriteBenchfmt writes the result as a line of the Go benchmark format,
+// one pass being one op, so that runs can be compared with benchstat.
+func (result *BenchResult) WriteBenchfmt(w io.Writer) error {
+	nsPerOp := float64(result.Elapsed.Nanoseconds()) / float64(result.Passes)
+	seconds := result.Elapsed.Seconds()
+	_, err := fmt.Fprintf(w, "BenchmarkChunker/algorithm=%s/corpus=%s %d %.0f ns/op %.2f MB/s %.2f chunks/s %.2f allocs/op %.4f dedup-ratio\n",
+		result.Algorithm, result.Corpus, result.Passes, nsPerOp,
+		float64(result.Bytes)/seconds/1e6, result.ChunksPerSec, result.AllocsPerPass, result.DedupRatio)
+	return err
+}
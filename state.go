@@ -0,0 +1,221 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+var ErrUnresumable = errors.New("chunker state cannot be saved with a Scanner, MaxLatency or a stateful algorithm not implementing encoding.BinaryMarshaler")
+var ErrState = errors.New("invalid chunker state")
+var ErrStateOptions = errors.New("chunker state saved with another algorithm or options")
+var ErrStatePeeked = errors.New("chunker state cannot be saved while a chunk is peeked")
+
+const stateMagic = "go-cdc-chunkers state v1\x00"
+
+// State returns the state of the chunker past the last chunk returned,
+// so that a stream interrupted there, such as by a restart, can resume
+// with ResumeChunker at the same cutpoints: the bytes read ahead, the
+// stream offset, the cut stats, the stream identity and the state of
+// stateful algorithms, which must implement encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler. The recent digests of NextChunk are not
+// kept, nor the bytes already handed out.
+func (chunker *Chunker) State() ([]byte, error) {
+	if chunker.scanner != nil || chunker.latency != nil {
+		return nil, ErrUnresumable
+	}
+	if chunker.peeked {
+		return nil, ErrStatePeeked
+	}
+
+	var implementation []byte
+	if chunker.stateful != nil {
+		marshaler, ok := chunker.implementation.(encoding.BinaryMarshaler)
+		if !ok {
+			return nil, ErrUnresumable
+		}
+		var err error
+		if implementation, err = marshaler.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
+	var identity []byte
+	if chunker.identity != nil {
+		var err error
+		if identity, err = chunker.identity.(encoding.BinaryMarshaler).MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
+
+	buffered := chunker.rd.buf[chunker.rd.r+chunker.cutpoint : chunker.rd.w]
+	state := []byte(stateMagic)
+	state = appendBytes(state, []byte(chunker.name))
+	state = append(state, newIdentity(chunker.name, chunker.options).Sum(nil)...)
+	state = binary.AppendVarint(state, chunker.position+int64(chunker.cutpoint))
+	for _, count := range []uint64{chunker.cuts.Chunks, chunker.cuts.Forced, chunker.cuts.LowEntropy, chunker.cuts.TimeCuts} {
+		state = binary.AppendUvarint(state, count)
+	}
+	state = appendBytes(state, buffered)
+	state = appendBytes(state, identity)
+	state = appendBytes(state, implementation)
+	return state, nil
+}
+
+// ResumeChunker returns a chunker resuming the stream whose state was
+// saved by State, with the same algorithm and options. r must yield the
+// stream from ResumeOffset on, the bytes read ahead being part of the
+// state. Offsets continue from those of the chunker the state was saved
+// from.
+func ResumeChunker(algorithm string, r io.Reader, state []byte, opts *ChunkerOpts) (*Chunker, error) {
+	chunker, err := NewChunker(algorithm, r, opts)
+	if err != nil {
+		return nil, err
+	}
+	if chunker.scanner != nil || chunker.latency != nil {
+		return nil, ErrUnresumable
+	}
+
+	s, err := decodeState(state)
+	if err != nil {
+		return nil, err
+	}
+	if s.name != chunker.name || !bytes.Equal(s.options, newIdentity(chunker.name, chunker.options).Sum(nil)) ||
+		(len(s.identity) != 0) != (chunker.identity != nil) {
+		return nil, ErrStateOptions
+	}
+	if len(s.buffered) > len(chunker.rd.buf) {
+		return nil, ErrState
+	}
+
+	if chunker.stateful != nil {
+		unmarshaler, ok := chunker.implementation.(encoding.BinaryUnmarshaler)
+		if !ok {
+			return nil, ErrUnresumable
+		}
+		if err := unmarshaler.UnmarshalBinary(s.implementation); err != nil {
+			return nil, err
+		}
+	}
+	if chunker.identity != nil {
+		if err := chunker.identity.(encoding.BinaryUnmarshaler).UnmarshalBinary(s.identity); err != nil {
+			return nil, ErrState
+		}
+	}
+	chunker.rd.w = copy(chunker.rd.buf, s.buffered)
+	chunker.position = s.position
+	chunker.scanned = s.position
+	chunker.cuts = s.cuts
+	return chunker, nil
+}
+
+// ResumeOffset returns the offset in the stream from which the reader
+// passed to ResumeChunker with state must start, past the bytes read
+// ahead that state holds.
+func ResumeOffset(state []byte) (int64, error) {
+	s, err := decodeState(state)
+	if err != nil {
+		return 0, err
+	}
+	return s.position + int64(len(s.buffered)), nil
+}
+
+// savedState is a state decoded, its slices aliasing the encoding.
+type savedState struct {
+	name           string
+	options        []byte
+	position       int64
+	cuts           CutStats
+	buffered       []byte
+	identity       []byte
+	implementation []byte
+}
+
+func decodeState(state []byte) (*savedState, error) {
+	if !bytes.HasPrefix(state, []byte(stateMagic)) {
+		return nil, ErrState
+	}
+	d := stateDecoder{data: state[len(stateMagic):]}
+	s := &savedState{}
+	s.name = string(d.bytes())
+	s.options = d.next(32)
+	s.position = d.varint()
+	for _, count := range []*uint64{&s.cuts.Chunks, &s.cuts.Forced, &s.cuts.LowEntropy, &s.cuts.TimeCuts} {
+		*count = d.uvarint()
+	}
+	s.buffered = d.bytes()
+	s.identity = d.bytes()
+	s.implementation = d.bytes()
+	if d.err != nil || len(d.data) != 0 || s.position < 0 {
+		return nil, ErrState
+	}
+	return s, nil
+}
+
+// appendBytes appends b to state, prefixed with its length.
+func appendBytes(state, b []byte) []byte {
+	state = binary.AppendUvarint(state, uint64(len(b)))
+	return append(state, b...)
+}
+
+// stateDecoder reads the fields of a state, failing once one is truncated.
+type stateDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *stateDecoder) next(n int) []byte {
+	if d.err != nil || n < 0 || n > len(d.data) {
+		d.err = ErrState
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *stateDecoder) uvarint() uint64 {
+	value, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = ErrState
+		return 0
+	}
+	d.data = d.data[n:]
+	return value
+}
+
+func (d *stateDecoder) varint() int64 {
+	value, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = ErrState
+		return 0
+	}
+	d.data = d.data[n:]
+	return value
+}
+
+func (d *stateDecoder) bytes() []byte {
+	length := d.uvarint()
+	if length > uint64(len(d.data)) {
+		d.err = ErrState
+		return nil
+	}
+	return d.next(int(length))
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+// ChunkOrErr is a chunk received from Stream, or the error that stopped
+// it.
+type ChunkOrErr struct {
+	Chunk Chunk
+	Err   error
+}
+
+// Stream returns NextChunk's chunks on a channel holding up to depth of
+// them, from another goroutine, so that consumers can fan them out to
+// workers. A depth below zero is taken as zero, an unbuffered channel.
+// Chunking blocks while the channel is full. The channel is
+// closed once the stream is exhausted, after a ChunkOrErr carrying the
+// error if one stopped it, or once ctx is done, ctx.Err() telling an
+// interrupted stream from an exhausted one. Chunks are owned copies even
+// with BorrowBuffers. The chunker must not be used until the channel is
+// closed.
+func (chunker *Chunker) Stream(ctx context.Context, depth int) <-chan ChunkOrErr {
+	ch := make(chan ChunkOrErr, max(0, depth))
+	go func() {
+		defer close(ch)
+		for ctx.Err() == nil {
+			chunk, err := chunker.NextChunk()
+			if err != nil && err != io.EOF {
+				select {
+				case ch <- ChunkOrErr{Err: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+			if len(chunk.Data) != 0 {
+				if chunker.options.BorrowBuffers {
+					chunk.Data = bytes.Clone(chunk.Data)
+					chunk.Digest = bytes.Clone(chunk.Digest)
+				}
+				select {
+				case ch <- ChunkOrErr{Chunk: chunk}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err == io.EOF {
+				return
+			}
+		}
+	}()
+	return ch
+}
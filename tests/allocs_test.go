@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/bupsplit"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/casync"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/fixed"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/gear"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/maxp"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/mii"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/pci"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/quickcdc"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/rapidcdc"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/restic"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/rsync"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/seqcdc"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/winnowing"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/zstd"
+)
+
+// restic defaults to 1MiB chunks, keep the runs within the input
+func allocsOpts() *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+}
+
+// Steady-state chunking only allocates the owned copy of each chunk: the
+// chunker owns a single buffer and algorithms work in place, so borrowing
+// buffers brings allocations down to zero.
+func Test_Next_Allocs(t *testing.T) {
+	for _, algorithm := range []string{"fastcdc", "fastcdc2020", "jc", "ultracdc", "gear", "bupsplit", "mii", "restic", "casync", "quickcdc", "rapidcdc", "sourcecode", "seqcdc", "pci", "maxp", "fixed", "winnowing", "rsync", "lbfs", "zstd-rsyncable"} {
+		for _, borrow := range []bool{false, true} {
+			opts := allocsOpts()
+			opts.BorrowBuffers = borrow
+			chunker, err := chunkers.NewChunker(algorithm, bytes.NewReader(rb[:256<<20]), opts)
+			if err != nil {
+				t.Fatalf(`chunker error: %s`, err)
+			}
+			allocs := testing.AllocsPerRun(1000, func() {
+				if _, err := chunker.Next(); err != nil {
+					t.Fatalf(`chunker error: %s`, err)
+				}
+			})
+			expected := 1.0
+			if borrow {
+				expected = 0
+			}
+			if allocs != expected {
+				t.Fatalf(`%s: %f allocations per chunk with BorrowBuffers=%t, expected %f`, algorithm, allocs, borrow, expected)
+			}
+		}
+	}
+}
+
+// Splitting with borrowed buffers allocates when setting up the chunker,
+// never per chunk.
+func Test_Split_Allocs(t *testing.T) {
+	callback := func(offset, length uint64, chunk []byte) error {
+		return nil
+	}
+	split := func(algorithm string, data []byte) float64 {
+		r := bytes.NewReader(data)
+		return testing.AllocsPerRun(2, func() {
+			r.Reset(data)
+			opts := allocsOpts()
+			opts.BorrowBuffers = true
+			chunker, err := chunkers.NewChunker(algorithm, r, opts)
+			if err != nil {
+				t.Fatalf(`chunker error: %s`, err)
+			}
+			if err := chunker.Split(callback); err != nil {
+				t.Fatalf(`chunker error: %s`, err)
+			}
+		})
+	}
+	for _, algorithm := range []string{"fastcdc", "fastcdc2020", "jc", "ultracdc", "gear", "bupsplit", "mii", "restic", "casync", "quickcdc", "rapidcdc", "sourcecode", "seqcdc", "pci", "maxp", "fixed", "winnowing", "rsync", "lbfs", "zstd-rsyncable"} {
+		small := split(algorithm, rb[:1<<20])
+		large := split(algorithm, rb[:64<<20])
+		if small != large {
+			t.Fatalf(`%s: allocations grow with input size: %f vs %f`, algorithm, small, large)
+		}
+	}
+}
+
+// NextChunk hashes into a buffer of the chunker, so borrowing buffers
+// keeps it allocation free as well.
+func Test_NextChunk_Allocs(t *testing.T) {
+	for _, borrow := range []bool{false, true} {
+		opts := allocsOpts()
+		opts.BorrowBuffers = borrow
+		chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(rb[:256<<20]), opts)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		allocs := testing.AllocsPerRun(1000, func() {
+			if _, err := chunker.NextChunk(); err != nil {
+				t.Fatalf(`chunker error: %s`, err)
+			}
+		})
+		// the owned copies of the chunk and of its digest
+		expected := 2.0
+		if borrow {
+			expected = 0
+		}
+		if allocs != expected {
+			t.Fatalf(`%f allocations per chunk with BorrowBuffers=%t, expected %f`, allocs, borrow, expected)
+		}
+	}
+}
+
+// NextBoundary never copies chunks out, BorrowBuffers or not.
+func Test_NextBoundary_Allocs(t *testing.T) {
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(rb[:256<<20]), allocsOpts())
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	allocs := testing.AllocsPerRun(1000, func() {
+		if _, _, err := chunker.NextBoundary(); err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf(`%f allocations per boundary, expected none`, allocs)
+	}
+}
@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/quickcdc"
+)
+
+func Test_NewChunkerAt(t *testing.T) {
+	data := rb[:16<<20]
+	r := bytes.NewReader(data)
+
+	for _, algorithm := range []string{"fastcdc", "fastcdc2020", "jc", "ultracdc", "gear", "bupsplit", "mii", "restic", "casync", "sourcecode", "seqcdc", "pci", "maxp", "fixed", "winnowing", "rsync", "lbfs", "zstd-rsyncable"} {
+		opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, MinTailSize: 1 << 10}
+
+		var boundaries []uint64
+		chunker, err := chunkers.NewChunkerAt(algorithm, r, 0, opts)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+			boundaries = append(boundaries, offset+length)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+
+		for _, i := range []int{0, 1, len(boundaries) / 2, len(boundaries) - 2} {
+			anchor := boundaries[i]
+			chunker, err := chunkers.NewChunkerAt(algorithm, r, int64(anchor), opts)
+			if err != nil {
+				t.Fatalf(`chunker error: %s`, err)
+			}
+			expected := boundaries[i+1:]
+			err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+				if offset != anchor {
+					t.Fatalf(`%s: expected a chunk at %d, got %d`, algorithm, anchor, offset)
+				}
+				if !bytes.Equal(chunk, data[offset:offset+length]) {
+					t.Fatalf(`%s: chunk at %d does not match the input`, algorithm, offset)
+				}
+				if len(expected) == 0 || offset+length != expected[0] {
+					t.Fatalf(`%s: resumed at %d, boundary %d does not match the full run`, algorithm, boundaries[i], offset+length)
+				}
+				anchor, expected = offset+length, expected[1:]
+				return nil
+			})
+			if err != nil {
+				t.Fatalf(`chunker error: %s`, err)
+			}
+			if len(expected) != 0 {
+				t.Fatalf(`%s: resumed at %d, %d boundaries missing`, algorithm, boundaries[i], len(expected))
+			}
+		}
+	}
+
+	if _, err := chunkers.NewChunkerAt("quickcdc", r, 0, nil); err != chunkers.ErrStatefulAnchor {
+		t.Fatalf(`expected ErrStatefulAnchor, got %v`, err)
+	}
+}
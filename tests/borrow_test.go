@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+// Without BorrowBuffers chunks are owned and survive later reads.
+func Test_OwnedChunks(t *testing.T) {
+	data := rb[:16<<20]
+
+	var retained [][]byte
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		retained = append(retained, chunk)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if !bytes.Equal(bytes.Join(retained, nil), data) {
+		t.Fatalf(`retained Split chunks were overwritten`)
+	}
+
+	retained = retained[:0]
+	chunker, err = chunkers.NewChunker("fastcdc", bytes.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	for {
+		chunk, err := chunker.Next()
+		if err != nil && err != io.EOF {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		retained = append(retained, chunk)
+		if err == io.EOF {
+			break
+		}
+	}
+	if !bytes.Equal(bytes.Join(retained, nil), data) {
+		t.Fatalf(`retained Next chunks were overwritten`)
+	}
+}
+
+// With BorrowBuffers chunks alias the internal buffer, which is reused.
+func Test_BorrowedChunks(t *testing.T) {
+	data := rb[:16<<20]
+
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, BorrowBuffers: true}
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	first, err := chunker.Next()
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if !bytes.Equal(first, data[:len(first)]) {
+		t.Fatalf(`borrowed chunk does not match the input`)
+	}
+	saved := append([]byte(nil), first...)
+	for i := 0; i < 64; i++ {
+		if _, err := chunker.Next(); err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+	}
+	if bytes.Equal(first, saved) {
+		t.Fatalf(`borrowed chunk was not reused, expected aliasing of the internal buffer`)
+	}
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+	"testing/iotest"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+// cuts returns the offsets of the chunks cut by chunker.
+func cuts(t *testing.T, chunker *chunkers.Chunker) []uint64 {
+	var offsets []uint64
+	err := chunker.Split(func(offset, length uint64, chunk []byte) error {
+		offsets = append(offsets, offset)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	return offsets
+}
+
+func Test_NewChunkerWithBuffer(t *testing.T) {
+	data := rb[:8<<20]
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	size := chunkers.RequiredBufferSize(opts)
+	if size < opts.MaxSize {
+		t.Fatalf(`required buffer of %d bytes for a MaxSize of %d`, size, opts.MaxSize)
+	}
+
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	expected := cuts(t, chunker)
+
+	// whatever the buffer and however the reader splits reads, chunkers
+	// cut at the same offsets
+	buf := make([]byte, 3*size)
+	for _, b := range [][]byte{buf[:size], buf, buf[1 : size+1]} {
+		chunker, err := chunkers.NewChunkerWithBuffer("fastcdc", bytes.NewReader(data), opts, b)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		if offsets := cuts(t, chunker); !slices.Equal(offsets, expected) {
+			t.Fatalf(`buffer of %d bytes cut %d chunks, %d expected`, len(b), len(offsets), len(expected))
+		}
+		if chunker.MemoryFootprint() != len(b) {
+			t.Fatalf(`memory footprint of %d bytes with a buffer of %d`, chunker.MemoryFootprint(), len(b))
+		}
+
+		chunker, err = chunkers.NewChunkerWithBuffer("fastcdc", iotest.OneByteReader(bytes.NewReader(data[:1<<20])), opts, b)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		if offsets := cuts(t, chunker); !slices.Equal(offsets[:len(offsets)-1], expected[:len(offsets)-1]) {
+			t.Fatalf(`buffer of %d bytes cut other chunks from short reads`, len(b))
+		}
+	}
+
+	// a buffer too small for MaxSize is refused
+	for _, b := range [][]byte{nil, buf[:size-1]} {
+		if _, err := chunkers.NewChunkerWithBuffer("fastcdc", bytes.NewReader(data), opts, b); !errors.Is(err, chunkers.ErrBufferSize) {
+			t.Fatalf(`buffer of %d bytes: got %v, expected %v`, len(b), err, chunkers.ErrBufferSize)
+		}
+	}
+}
+
+// Chunkers created over a pooled buffer only allocate themselves, their
+// implementation and their reader.
+func Test_NewChunkerWithBuffer_Allocs(t *testing.T) {
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, BorrowBuffers: true}
+	buf := make([]byte, chunkers.RequiredBufferSize(opts))
+	r := bytes.NewReader(nil)
+	callback := func(offset, length uint64, chunk []byte) error {
+		return nil
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		r.Reset(rb[:20<<10])
+		chunker, err := chunkers.NewChunkerWithBuffer("fastcdc", r, opts, buf)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		if err := chunker.Split(callback); err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+	})
+	if allocs > 3 {
+		t.Fatalf(`%v allocations per chunker, expected no buffer`, allocs)
+	}
+}
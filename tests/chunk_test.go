@@ -0,0 +1,192 @@
+package tests
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_NextChunk(t *testing.T) {
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	data := rb[:4<<20]
+	lengths := splitLengths(t, "fastcdc", data, opts)
+	anchor := uint64(lengths[0])
+
+	for _, borrow := range []bool{false, true} {
+		// the chunks and digests of SplitDigest from the anchor on
+		var expected []chunkers.Chunk
+		chunker, err := chunkers.NewChunkerAt("fastcdc", bytes.NewReader(data), int64(anchor), opts)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		err = chunker.SplitDigest(func(offset, length uint64, chunk []byte, digest []byte) error {
+			expected = append(expected, chunkers.Chunk{Offset: uint64(offset), Length: uint32(length), Digest: bytes.Clone(digest)})
+			return nil
+		})
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+
+		borrowed := *opts
+		borrowed.BorrowBuffers = borrow
+		chunker, err = chunkers.NewChunkerAt("fastcdc", bytes.NewReader(data), int64(anchor), &borrowed)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		var chunks []chunkers.Chunk
+		for {
+			chunk, err := chunker.NextChunk()
+			if err != nil && err != io.EOF {
+				t.Fatalf(`chunker error: %s`, err)
+			}
+			if chunk.Length != 0 {
+				if int(chunk.Length) != len(chunk.Data) || !bytes.Equal(chunk.Data, data[chunk.Offset:chunk.Offset+uint64(chunk.Length)]) {
+					t.Fatalf(`chunk at %d does not match the stream`, chunk.Offset)
+				}
+				chunks = append(chunks, chunk)
+			}
+			if err == io.EOF {
+				break
+			}
+		}
+
+		if len(chunks) != len(expected) || chunks[0].Offset != anchor {
+			t.Fatalf(`%d chunks from %d, expected %d from %d`, len(chunks), chunks[0].Offset, len(expected), anchor)
+		}
+		for i, chunk := range chunks {
+			if borrow {
+				// only the last chunk returned is still valid
+				continue
+			}
+			if chunk.Offset != expected[i].Offset || chunk.Length != expected[i].Length || !bytes.Equal(chunk.Digest, expected[i].Digest) {
+				t.Fatalf(`chunk %d at %d of %d bytes, expected at %d of %d bytes`, i, chunk.Offset, chunk.Length, expected[i].Offset, expected[i].Length)
+			}
+		}
+		last := chunks[len(chunks)-1]
+		if !bytes.Equal(last.Digest, expected[len(expected)-1].Digest) {
+			t.Fatalf(`last digest mismatch with BorrowBuffers=%t`, borrow)
+		}
+	}
+}
+
+func Test_NextChunk_Repeat(t *testing.T) {
+	block := rb[:4<<10]
+	data := bytes.Repeat(block, 16)
+	opts := &chunkers.ChunkerOpts{MinSize: 1 << 10, NormalSize: 2 << 10, MaxSize: 4 << 10, RecentDigests: 4}
+	chunker, err := chunkers.NewChunker("fixed", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	repeats := 0
+	for {
+		chunk, err := chunker.NextChunk()
+		if err != nil && err != io.EOF {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		if chunk.Length != 0 && chunker.Flags()&chunkers.FlagRepeat != 0 {
+			repeats++
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	// every chunk but the two halves of the first block repeats
+	if repeats != len(data)/(2<<10)-2 {
+		t.Fatalf(`%d chunks flagged as repeats`, repeats)
+	}
+}
+
+func Test_NextBoundary(t *testing.T) {
+	data := rb[:4<<20]
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	var expected [][2]uint64
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		expected = append(expected, [2]uint64{uint64(offset), uint64(length)})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	chunker, err = chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	var boundaries [][2]uint64
+	for {
+		offset, length, err := chunker.NextBoundary()
+		if length != 0 {
+			boundaries = append(boundaries, [2]uint64{offset, uint64(length)})
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+	}
+	if !slices.Equal(boundaries, expected) {
+		t.Fatalf(`%d boundaries differ from the %d chunks split`, len(boundaries), len(expected))
+	}
+}
+
+func Test_SplitAll(t *testing.T) {
+	data := rb[:4<<20]
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	var expected []chunkers.Chunk
+	for {
+		chunk, err := chunker.NextChunk()
+		if len(chunk.Data) != 0 {
+			expected = append(expected, chunk)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+	}
+
+	chunks, err := chunkers.SplitAll("fastcdc", data, opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	digested, err := chunkers.SplitAllDigest("fastcdc", data, opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if len(chunks) != len(expected) || len(digested) != len(expected) {
+		t.Fatalf(`%d and %d chunks, %d expected`, len(chunks), len(digested), len(expected))
+	}
+	for i := range expected {
+		if chunks[i].Offset != expected[i].Offset || chunks[i].Length != expected[i].Length ||
+			!bytes.Equal(chunks[i].Data, expected[i].Data) || chunks[i].Digest != nil {
+			t.Fatalf(`chunk %d differs`, i)
+		}
+		// chunks alias data
+		if &chunks[i].Data[0] != &data[chunks[i].Offset] {
+			t.Fatalf(`chunk %d copied`, i)
+		}
+		if !bytes.Equal(digested[i].Digest, expected[i].Digest) {
+			t.Fatalf(`chunk %d has another digest`, i)
+		}
+	}
+
+	if chunks, err := chunkers.SplitAll("fastcdc", nil, nil); len(chunks) != 0 || err != nil {
+		t.Fatalf(`%d chunks of an empty buffer: %v`, len(chunks), err)
+	}
+	if _, err := chunkers.SplitAll("unknown", data, nil); err == nil {
+		t.Fatalf(`unknown algorithm accepted`)
+	}
+}
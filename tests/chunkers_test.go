@@ -127,7 +127,7 @@ func Test_FastCDC_Split(t *testing.T) {
 	}
 
 	saw_minsize := false
-	w := func(offset, length uint, chunk []byte) error {
+	w := func(offset, length uint64, chunk []byte) error {
 		if len(chunk) < int(chunker.MinSize()) {
 			if saw_minsize != false {
 				t.Fatalf(`chunker return a chunk below MinSize before last chunk: %d < %d`, len(chunk), int(chunker.MinSize()))
@@ -240,7 +240,7 @@ func Test_JC_Split(t *testing.T) {
 	}
 
 	saw_minsize := false
-	w := func(offset, length uint, chunk []byte) error {
+	w := func(offset, length uint64, chunk []byte) error {
 		if len(chunk) < int(chunker.MinSize()) {
 			if saw_minsize != false {
 				t.Fatalf(`chunker return a chunk below MinSize before last chunk: %d < %d`, len(chunk), int(chunker.MinSize()))
@@ -353,7 +353,7 @@ func Test_UltraCDC_Split(t *testing.T) {
 	}
 
 	saw_minsize := false
-	w := func(offset, length uint, chunk []byte) error {
+	w := func(offset, length uint64, chunk []byte) error {
 		if len(chunk) < int(chunker.MinSize()) {
 			if saw_minsize != false {
 				t.Fatalf(`chunker return a chunk below MinSize before last chunk: %d < %d`, len(chunk), int(chunker.MinSize()))
@@ -533,7 +533,7 @@ func Benchmark_PlakarKorp_FastCDC_Split(b *testing.B) {
 		MaxSize:    maxSize,
 	}
 
-	w := func(offset, length uint, chunk []byte) error {
+	w := func(offset, length uint64, chunk []byte) error {
 		nchunks++
 		return nil
 	}
@@ -578,6 +578,91 @@ func Benchmark_PlakarKorp_FastCDC_Next(b *testing.B) {
 	b.ReportMetric(float64(nchunks)/float64(b.N), "chunks")
 }
 
+func Benchmark_PlakarKorp_FastCDC2020_Copy(b *testing.B) {
+	r := bytes.NewReader(rb)
+	b.SetBytes(int64(r.Len()))
+	b.ResetTimer()
+	nchunks := 0
+
+	opts := &chunkers.ChunkerOpts{
+		MinSize:    minSize,
+		NormalSize: avgSize,
+		MaxSize:    maxSize,
+	}
+
+	w := writerFunc(func(p []byte) (int, error) {
+		nchunks++
+		return len(p), nil
+	})
+
+	for i := 0; i < b.N; i++ {
+		chunker, err := chunkers.NewChunker("fastcdc2020", r, opts)
+		if err != nil {
+			b.Fatalf(`chunker error: %s`, err)
+		}
+		chunker.Copy(w)
+		r.Reset(rb)
+	}
+	b.ReportMetric(float64(nchunks)/float64(b.N), "chunks")
+}
+
+func Benchmark_PlakarKorp_FastCDC2020_Split(b *testing.B) {
+	r := bytes.NewReader(rb)
+	b.SetBytes(int64(r.Len()))
+	b.ResetTimer()
+	nchunks := 0
+
+	opts := &chunkers.ChunkerOpts{
+		MinSize:    minSize,
+		NormalSize: avgSize,
+		MaxSize:    maxSize,
+	}
+
+	w := func(offset, length uint64, chunk []byte) error {
+		nchunks++
+		return nil
+	}
+
+	for i := 0; i < b.N; i++ {
+		chunker, err := chunkers.NewChunker("fastcdc2020", r, opts)
+		if err != nil {
+			b.Fatalf(`chunker error: %s`, err)
+		}
+		err = chunker.Split(w)
+		if err != nil && err != io.EOF {
+			b.Fatalf(`chunker error: %s`, err)
+		}
+		r.Reset(rb)
+	}
+	b.ReportMetric(float64(nchunks)/float64(b.N), "chunks")
+}
+
+func Benchmark_PlakarKorp_FastCDC2020_Next(b *testing.B) {
+	r := bytes.NewReader(rb)
+	b.SetBytes(int64(r.Len()))
+	b.ResetTimer()
+	nchunks := 0
+
+	opts := &chunkers.ChunkerOpts{
+		MinSize:    minSize,
+		NormalSize: avgSize,
+		MaxSize:    maxSize,
+	}
+
+	for i := 0; i < b.N; i++ {
+		chunker, err := chunkers.NewChunker("fastcdc2020", r, opts)
+		if err != nil {
+			b.Fatalf(`chunker error: %s`, err)
+		}
+		for err := error(nil); err == nil; {
+			_, err = chunker.Next()
+			nchunks++
+		}
+		r.Reset(rb)
+	}
+	b.ReportMetric(float64(nchunks)/float64(b.N), "chunks")
+}
+
 func Benchmark_PlakarKorp_UltraCDC_Copy(b *testing.B) {
 	r := bytes.NewReader(rb)
 	b.SetBytes(int64(r.Len()))
@@ -618,7 +703,7 @@ func Benchmark_PlakarKorp_UltraCDC_Split(b *testing.B) {
 		MaxSize:    maxSize,
 	}
 
-	w := func(offset, length uint, chunk []byte) error {
+	w := func(offset, length uint64, chunk []byte) error {
 		nchunks++
 		return nil
 	}
@@ -703,7 +788,7 @@ func Benchmark_PlakarKorp_JC_Split(b *testing.B) {
 		MaxSize:    maxSize,
 	}
 
-	w := func(offset, length uint, chunk []byte) error {
+	w := func(offset, length uint64, chunk []byte) error {
 		nchunks++
 		return nil
 	}
@@ -0,0 +1,36 @@
+package tests
+
+import (
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_Compare(t *testing.T) {
+	original := rb[:8<<20]
+	mutated := append(append(append([]byte{}, original[:4<<20]...), "mutation"...), original[4<<20:]...)
+
+	comparison, err := chunkers.Compare("fastcdc", "fastcdc", nil, original, mutated)
+	if err != nil {
+		t.Fatalf(`compare error: %s`, err)
+	}
+	if comparison.BoundaryOverlap != 1 || comparison.SharedBoundaries != comparison.A.Chunks {
+		t.Fatalf(`an algorithm should fully overlap with itself`)
+	}
+
+	comparison, err = chunkers.Compare("fastcdc", "ultracdc", nil, original, mutated)
+	if err != nil {
+		t.Fatalf(`compare error: %s`, err)
+	}
+	for _, report := range []chunkers.AlgorithmReport{comparison.A, comparison.B} {
+		if report.Chunks == 0 || report.AverageSize == 0 {
+			t.Fatalf(`%s: no chunks reported`, report.Algorithm)
+		}
+		if report.DedupRatio < 0.9 || report.DedupRatio >= 1 {
+			t.Fatalf(`%s: unexpected dedup ratio %f`, report.Algorithm, report.DedupRatio)
+		}
+	}
+	if comparison.BoundaryOverlap >= 1 {
+		t.Fatalf(`distinct algorithms should not fully overlap`)
+	}
+}
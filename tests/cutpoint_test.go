@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+)
+
+func Test_FindCutpoint(t *testing.T) {
+	data := rb[:4<<20]
+	for _, algorithm := range []string{"fastcdc", "jc", "ultracdc", "gear", "bupsplit"} {
+		opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+		expected := splitLengths(t, algorithm, data, opts)
+
+		compiled, err := opts.Compile(algorithm)
+		if err != nil {
+			t.Fatalf(`%s: options rejected: %s`, algorithm, err)
+		}
+		var lengths []int
+		for offset := 0; offset < len(data); {
+			cutpoint := compiled.FindCutpoint(data[offset:])
+			if once, err := chunkers.FindCutpoint(algorithm, opts, data[offset:]); err != nil || once != cutpoint {
+				t.Fatalf(`%s: cutpoint %d compiled, %d otherwise: %v`, algorithm, cutpoint, once, err)
+			}
+			lengths = append(lengths, cutpoint)
+			offset += cutpoint
+		}
+		if !slices.Equal(lengths, expected) {
+			t.Fatalf(`%s: cutpoints differ from those of a chunker`, algorithm)
+		}
+	}
+
+	if cutpoint, err := chunkers.FindCutpoint("fastcdc", nil, nil); cutpoint != 0 || err != nil {
+		t.Fatalf(`cutpoint %d of an empty buffer: %v`, cutpoint, err)
+	}
+	invalid := &chunkers.ChunkerOpts{MinSize: 16 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	if _, err := chunkers.FindCutpoint("fastcdc", invalid, data); !errors.Is(err, fastcdc.ErrMinSize) {
+		t.Fatalf(`expected ErrMinSize, got %v`, err)
+	}
+	if _, err := chunkers.FindCutpoint("quickcdc", nil, data); err != chunkers.ErrStateful {
+		t.Fatalf(`expected ErrStateful, got %v`, err)
+	}
+	if _, err := chunkers.FindCutpoint("unknown", nil, data); err == nil {
+		t.Fatalf(`unknown algorithm accepted`)
+	}
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_SplitDedup(t *testing.T) {
+	// the same 4MiB twice: the second half is entirely made of duplicates
+	// except around the seam.
+	data := append(append([]byte{}, rb[:4<<20]...), rb[:4<<20]...)
+
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	seen := make(map[string]bool)
+	has := func(digest []byte) bool {
+		if seen[string(digest)] {
+			return true
+		}
+		seen[string(digest)] = true
+		return false
+	}
+
+	var last chunkers.DedupStats
+	updates := 0
+	err = chunker.SplitDedup(has, func(stats chunkers.DedupStats) {
+		last = stats
+		updates++
+	}, func(offset, length uint64, chunk []byte) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	if updates == 0 || last.Chunks != uint64(updates) {
+		t.Fatalf(`expected one stats update per chunk`)
+	}
+	if last.Bytes != uint64(len(data)) {
+		t.Fatalf(`expected %d bytes, got %d`, len(data), last.Bytes)
+	}
+	if last.Ratio() < 0.45 || last.Ratio() > 0.5 {
+		t.Fatalf(`unexpected dedup ratio %f`, last.Ratio())
+	}
+}
+
+func Test_SplitDedup_RecentDigests(t *testing.T) {
+	// a 4KiB block repeated 16 times, then another 4KiB block twice
+	var data []byte
+	for i := 0; i < 16; i++ {
+		data = append(data, rb[:4<<10]...)
+	}
+	data = append(data, rb[4<<10:8<<10]...)
+	data = append(data, rb[4<<10:8<<10]...)
+
+	opts := &chunkers.ChunkerOpts{MinSize: 1 << 10, NormalSize: 4 << 10, MaxSize: 8 << 10, RecentDigests: 4}
+	chunker, err := chunkers.NewChunker("fixed", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	lookups := 0
+	has := func(digest []byte) bool {
+		lookups++
+		return false
+	}
+	var last chunkers.DedupStats
+	repeats := 0
+	err = chunker.SplitDedup(has, func(stats chunkers.DedupStats) {
+		last = stats
+	}, func(offset, length uint64, chunk []byte) error {
+		if chunker.Flags()&chunkers.FlagRepeat != 0 {
+			repeats++
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	if lookups != 2 || repeats != 16 {
+		t.Fatalf(`expected 2 lookups and 16 repeats, got %d and %d`, lookups, repeats)
+	}
+	if last.RepeatedChunks != 16 || last.DuplicateChunks != 16 {
+		t.Fatalf(`unexpected stats %+v`, last)
+	}
+}
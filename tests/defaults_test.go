@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/gear"
+)
+
+func Test_SetDefaultOptions(t *testing.T) {
+	defer chunkers.SetDefaultOptions("gear", nil)
+
+	opts := &chunkers.ChunkerOpts{MinSize: 4 << 10, NormalSize: 16 << 10, MaxSize: 128 << 10}
+	if err := chunkers.SetDefaultOptions("gear", opts); err != nil {
+		t.Fatalf(`options rejected: %s`, err)
+	}
+	// the options were copied
+	opts.MinSize = 0
+
+	chunker, err := chunkers.NewChunker("gear", bytes.NewReader(rb[:1<<20]), nil)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if chunker.MinSize() != 4<<10 || chunker.NormalSize() != 16<<10 || chunker.MaxSize() != 128<<10 {
+		t.Fatalf(`default options not applied`)
+	}
+
+	invalid := &chunkers.ChunkerOpts{MinSize: 16 << 10, NormalSize: 4 << 10, MaxSize: 128 << 10}
+	if err := chunkers.SetDefaultOptions("gear", invalid); !errors.Is(err, gear.ErrMinSize) {
+		t.Fatalf(`expected ErrMinSize, got %v`, err)
+	}
+	if err := chunkers.SetDefaultOptions("unknown", opts); err == nil {
+		t.Fatalf(`unknown algorithm accepted`)
+	}
+
+	if err := chunkers.SetDefaultOptions("gear", nil); err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	chunker, err = chunkers.NewChunker("gear", bytes.NewReader(rb[:1<<20]), nil)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if chunker.MinSize() != 2<<10 {
+		t.Fatalf(`algorithm defaults not restored`)
+	}
+}
@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"hash"
+	"hash/fnv"
+	mathrand2 "math/rand/v2"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_XXH64(t *testing.T) {
+	for input, expected := range map[string]uint64{
+		"":    0xef46db3751d8e999,
+		"a":   0xd24ec4f1a98c6e5b,
+		"abc": 0x44bc2cf5ad770999,
+	} {
+		h := chunkers.NewXXH64()
+		h.Write([]byte(input))
+		if h.Sum64() != expected {
+			t.Fatalf(`XXH64(%q): expected %016x, got %016x`, input, expected, h.Sum64())
+		}
+	}
+
+	// every tail path, cross-checked against github.com/cespare/xxhash
+	var seed [32]byte
+	data := make([]byte, 1<<20)
+	mathrand2.NewChaCha8(seed).Read(data)
+	for _, vector := range []struct {
+		length   int
+		expected uint64
+	}{
+		{0, 0xef46db3751d8e999},
+		{1, 0xe88501f984c486ff},
+		{3, 0x622e150e61f081a8},
+		{4, 0xe2e33450cd74b89d},
+		{7, 0xc96bbde4fb54cefa},
+		{8, 0x637805b10df473b3},
+		{31, 0x8c79e6b15e70098c},
+		{32, 0xe793623ac6c0fe3d},
+		{33, 0x7886fcd37d46d799},
+		{63, 0xd8b71386d5c8734e},
+		{64, 0x0b2cd1a113e55e19},
+		{100, 0x56861f68c60c6668},
+		{1 << 20, 0x2fdf9d9be408f352},
+	} {
+		h := chunkers.NewXXH64()
+		h.Write(data[:vector.length])
+		if h.Sum64() != vector.expected {
+			t.Fatalf(`XXH64 of %d bytes: expected %016x, got %016x`, vector.length, vector.expected, h.Sum64())
+		}
+
+		// streamed in uneven writes
+		h.Reset()
+		for remaining := data[:vector.length]; len(remaining) != 0; {
+			n := min(len(remaining), 1+len(remaining)%13)
+			h.Write(remaining[:n])
+			remaining = remaining[n:]
+		}
+		if h.Sum64() != vector.expected {
+			t.Fatalf(`streamed XXH64 of %d bytes: expected %016x, got %016x`, vector.length, vector.expected, h.Sum64())
+		}
+	}
+}
+
+func Test_SplitDigest(t *testing.T) {
+	data := rb[:8<<20]
+
+	for _, newHash := range []func() hash.Hash{
+		nil,
+		func() hash.Hash { return chunkers.NewXXH64() },
+		func() hash.Hash { return fnv.New128a() },
+	} {
+		reference := newHash
+		if reference == nil {
+			reference = sha256.New
+		}
+
+		opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, Hash: newHash}
+		chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		chunks := 0
+		err = chunker.SplitDigest(func(offset, length uint64, chunk []byte, digest []byte) error {
+			h := reference()
+			h.Write(chunk)
+			if !bytes.Equal(digest, h.Sum(nil)) {
+				t.Fatalf(`chunk at %d: digest mismatch`, offset)
+			}
+			chunks++
+			return nil
+		})
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		if chunks == 0 {
+			t.Fatalf(`no chunk produced`)
+		}
+	}
+}
+
+func Benchmark_Digest_SHA256(b *testing.B) {
+	benchmarkDigest(b, nil)
+}
+
+func Benchmark_Digest_XXH64(b *testing.B) {
+	benchmarkDigest(b, func() hash.Hash { return chunkers.NewXXH64() })
+}
+
+func benchmarkDigest(b *testing.B, newHash func() hash.Hash) {
+	r := bytes.NewReader(rb)
+	b.SetBytes(int64(r.Len()))
+	b.ResetTimer()
+
+	opts := &chunkers.ChunkerOpts{
+		MinSize:       minSize,
+		NormalSize:    avgSize,
+		MaxSize:       maxSize,
+		BorrowBuffers: true,
+		Hash:          newHash,
+	}
+	callback := func(offset, length uint64, chunk []byte, digest []byte) error {
+		return nil
+	}
+
+	for i := 0; i < b.N; i++ {
+		chunker, err := chunkers.NewChunker("ultracdc", r, opts)
+		if err != nil {
+			b.Fatalf(`chunker error: %s`, err)
+		}
+		if err := chunker.SplitDigest(callback); err != nil {
+			b.Fatalf(`chunker error: %s`, err)
+		}
+		r.Reset(rb)
+	}
+}
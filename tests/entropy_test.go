@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/restic"
+)
+
+func entropy(t *testing.T, algorithm string, opts *chunkers.ChunkerOpts) []chunkers.EntropyInput {
+	chunker, err := chunkers.NewChunker(algorithm, bytes.NewReader(nil), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	return chunker.Entropy()
+}
+
+func Test_Entropy(t *testing.T) {
+	fastcdc := entropy(t, "fastcdc", nil)
+	if len(fastcdc) != 1 || fastcdc[0].Name != "gear table" {
+		t.Fatalf(`unexpected fastcdc entropy %v`, fastcdc)
+	}
+	for _, algorithm := range []string{"fastcdc2020", "gear", "quickcdc", "rapidcdc", "sourcecode", "winnowing"} {
+		if inputs := entropy(t, algorithm, nil); len(inputs) != 1 || inputs[0] != fastcdc[0] {
+			t.Fatalf(`%s does not report the fastcdc Gear table`, algorithm)
+		}
+	}
+	if inputs := entropy(t, "ultracdc", nil); inputs != nil {
+		t.Fatalf(`ultracdc reported entropy inputs %v`, inputs)
+	}
+
+	// defaults are resolved, and the digest can be recomputed from the value
+	var encoded [8]byte
+	binary.LittleEndian.PutUint64(encoded[:], restic.DefaultPolynomial)
+	defaults := entropy(t, "restic", nil)
+	if len(defaults) != 1 || defaults[0].Name != "polynomial" || defaults[0].Digest != sha256.Sum256(encoded[:]) {
+		t.Fatalf(`unexpected restic entropy %v`, defaults)
+	}
+
+	polynomial, err := restic.DerivePolynomial(bytes.NewReader(rb[:1<<20]))
+	if err != nil {
+		t.Fatalf(`polynomial error: %s`, err)
+	}
+	seeded := entropy(t, "restic", &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, Polynomial: polynomial})
+	if seeded[0] == defaults[0] {
+		t.Fatalf(`a derived polynomial reported the default one`)
+	}
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/gear"
+)
+
+// overflowing cuts past the data it is handed
+type overflowing struct {
+	gear.Gear
+}
+
+func (o *overflowing) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	return n + 1
+}
+
+func init() {
+	chunkers.MustRegister("test-overflowing", func() chunkers.ChunkerImplementation { return &overflowing{} })
+}
+
+func Test_Errors(t *testing.T) {
+	if _, err := chunkers.NewChunker("unknown", bytes.NewReader(nil), nil); !errors.Is(err, chunkers.ErrUnknownAlgorithm) {
+		t.Fatalf(`expected ErrUnknownAlgorithm, got %v`, err)
+	}
+	if _, err := chunkers.NewOptions("unknown"); !errors.Is(err, chunkers.ErrUnknownAlgorithm) {
+		t.Fatalf(`expected ErrUnknownAlgorithm, got %v`, err)
+	}
+	if _, err := chunkers.Resolve("unknown"); !errors.Is(err, chunkers.ErrUnknownAlgorithm) {
+		t.Fatalf(`expected ErrUnknownAlgorithm, got %v`, err)
+	}
+
+	// options refused by an algorithm match both ErrInvalidOptions and
+	// the error of the algorithm
+	invalid := &chunkers.ChunkerOpts{MinSize: 32 << 10, NormalSize: 16 << 10, MaxSize: 64 << 10}
+	err := chunkers.SetDefaultOptions("fastcdc", invalid)
+	if !errors.Is(err, chunkers.ErrInvalidOptions) || !errors.Is(err, fastcdc.ErrMinSize) {
+		t.Fatalf(`expected ErrInvalidOptions and ErrMinSize, got %v`, err)
+	}
+	if errors.Is(err, chunkers.ErrUnknownAlgorithm) {
+		t.Fatalf(`invalid options reported as an unknown algorithm`)
+	}
+	inconsistent := &chunkers.ChunkerOpts{MinSize: 64 << 10, NormalSize: 8 << 10, MaxSize: 2 << 10}
+	_, err = chunkers.NewChunker("fastcdc", bytes.NewReader(rb[:1<<20]), inconsistent)
+	if !errors.Is(err, chunkers.ErrInvalidOptions) || !errors.Is(err, fastcdc.ErrMinSize) {
+		t.Fatalf(`expected ErrInvalidOptions and ErrMinSize from NewChunker, got %v`, err)
+	}
+	if _, err := chunkers.NewChunker("fastcdc", bytes.NewReader(nil), &chunkers.ChunkerOpts{}); !errors.Is(err, chunkers.ErrInvalidOptions) {
+		t.Fatalf(`expected ErrInvalidOptions for zero options, got %v`, err)
+	}
+	if _, err := chunkers.NewOptions("fixed", chunkers.WithKey([]byte("key"))); !errors.Is(err, chunkers.ErrInvalidOptions) {
+		t.Fatalf(`expected ErrUnkeyed to match ErrInvalidOptions, got %v`, err)
+	}
+
+	chunker, err := chunkers.NewChunker("test-overflowing", bytes.NewReader(rb[:1<<20]), nil)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if _, err := chunker.Next(); err != chunkers.ErrChunkTooLarge {
+		t.Fatalf(`expected ErrChunkTooLarge, got %v`, err)
+	}
+}
+
+// Read errors are wrapped along with the offset of the first byte that
+// could not be read.
+func Test_Errors_Read(t *testing.T) {
+	errRead := errors.New("read failure")
+	r := io.MultiReader(bytes.NewReader(rb[:100<<10]), iotest.ErrReader(errRead))
+	chunker, err := chunkers.NewChunker("fastcdc", r, nil)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		return nil
+	})
+	if !errors.Is(err, errRead) {
+		t.Fatalf(`expected the read error, got %v`, err)
+	}
+	if !strings.Contains(err.Error(), "offset 102400") {
+		t.Fatalf(`offset missing from %q`, err)
+	}
+}
@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_EstimateChunks(t *testing.T) {
+	data := rb[:256<<20]
+
+	for _, algorithm := range []string{"fastcdc", "ultracdc"} {
+		actual := len(splitLengths(t, algorithm, data, nil))
+
+		estimate, err := chunkers.EstimateChunks(algorithm, nil, bytes.NewReader(data), int64(len(data)), 32, 1)
+		if err != nil {
+			t.Fatalf(`estimate error: %s`, err)
+		}
+		if estimate.ChunksLow > estimate.Chunks || estimate.Chunks > estimate.ChunksHigh {
+			t.Fatalf(`%s: estimate outside of its own interval`, algorithm)
+		}
+		if math.Abs(estimate.Chunks-float64(actual))/float64(actual) > 0.1 {
+			t.Fatalf(`%s: estimated %f chunks, actual %d`, algorithm, estimate.Chunks, actual)
+		}
+		if estimate.DedupRatio != 0 {
+			t.Fatalf(`%s: random data should not dedup, got %f`, algorithm, estimate.DedupRatio)
+		}
+		if estimate.SampledBytes >= int64(len(data))/2 {
+			t.Fatalf(`%s: sampled too much of the input`, algorithm)
+		}
+	}
+
+	// small inputs are chunked entirely and the estimate is exact
+	small := rb[:1<<20]
+	estimate, err := chunkers.EstimateChunks("fastcdc", nil, bytes.NewReader(small), int64(len(small)), 4, 1)
+	if err != nil {
+		t.Fatalf(`estimate error: %s`, err)
+	}
+	if int(estimate.Chunks) != len(splitLengths(t, "fastcdc", small, nil)) {
+		t.Fatalf(`expected an exact estimate on small inputs`)
+	}
+}
@@ -0,0 +1,25 @@
+package tests
+
+import (
+	"slices"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+// Rolling two bytes at a time is an optimization: it must not move cuts.
+func Test_FastCDC2020_Cutpoints(t *testing.T) {
+	for _, opts := range []*chunkers.ChunkerOpts{
+		nil,
+		{MinSize: minSize, NormalSize: avgSize, MaxSize: maxSize},
+		{MinSize: 2<<10 + 1, NormalSize: 8 << 10, MaxSize: 64<<10 - 1},
+		{MinSize: 2 << 10, NormalSize: 8<<10 + 1, MaxSize: 64 << 10, NormalizationLevel: 1},
+		{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 9<<10 + 1, NormalizationLevel: 3},
+		{MinSize: 2<<10 + 1, NormalSize: 8 << 10, MaxSize: 64 << 10, NormalizationLevel: -1},
+	} {
+		expected := splitLengths(t, "fastcdc", rb[:32<<20+3], opts)
+		if lengths := splitLengths(t, "fastcdc2020", rb[:32<<20+3], opts); !slices.Equal(expected, lengths) {
+			t.Fatalf(`%v: fastcdc2020 cutpoints differ from fastcdc`, opts)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package tests
+
+import (
+	"slices"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_Features(t *testing.T) {
+	features := chunkers.Features()
+	if features.Version != chunkers.Version() || features.Version == "" {
+		t.Fatalf(`unexpected version %q`, features.Version)
+	}
+	if !slices.IsSorted(features.Algorithms) || !slices.Contains(features.Algorithms, "fastcdc@v1") || !slices.Contains(features.Algorithms, "restic@v1") {
+		t.Fatalf(`unexpected algorithms %v`, features.Algorithms)
+	}
+	if !slices.Equal(features.Unsafe, []string{"fastcdc2020@v1", "fastcdc2020@v2", "fastcdc@v1", "fastcdc@v2", "jc@v1", "jc@v2"}) {
+		t.Fatalf(`unexpected unsafe algorithms %v`, features.Unsafe)
+	}
+	if features.SIMD {
+		t.Fatalf(`SIMD reported without vectorized code paths`)
+	}
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_CutStats(t *testing.T) {
+	// a MaxSize barely above NormalSize forces many cuts
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 9 << 10}
+
+	for _, algorithm := range []string{"fastcdc", "ultracdc"} {
+		chunker, err := chunkers.NewChunker(algorithm, bytes.NewReader(rb[:16<<20]), opts)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		var chunks, forced uint64
+		for {
+			chunk, err := chunker.Next()
+			if err != nil && err != io.EOF {
+				t.Fatalf(`chunker error: %s`, err)
+			}
+			if len(chunk) != 0 {
+				chunks++
+				if chunker.Flags()&chunkers.FlagForced != 0 {
+					if len(chunk) != opts.MaxSize {
+						t.Fatalf(`%s: chunk of %d bytes flagged as forced`, algorithm, len(chunk))
+					}
+					forced++
+				}
+			}
+			if err == io.EOF {
+				break
+			}
+		}
+
+		stats := chunker.CutStats()
+		if stats.Chunks != chunks || stats.Forced != forced || stats.LowEntropy != 0 {
+			t.Fatalf(`%s: expected %d chunks and %d forced, got %+v`, algorithm, chunks, forced, stats)
+		}
+		if stats.ForcedRatio() < 0.2 {
+			t.Fatalf(`%s: expected many forced cuts, got a ratio of %f`, algorithm, stats.ForcedRatio())
+		}
+	}
+}
+
+func Test_CutStats_LowEntropy(t *testing.T) {
+	chunker, err := chunkers.NewChunker("ultracdc", bytes.NewReader(make([]byte, 1<<20)), nil)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		if offset+length < 1<<20 && chunker.Flags() != chunkers.FlagLowEntropy {
+			t.Fatalf(`chunk at offset %d flagged %d`, offset, chunker.Flags())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if stats := chunker.CutStats(); stats.LowEntropy == 0 || stats.Forced != 0 {
+		t.Fatalf(`unexpected stats %+v on zeroes`, stats)
+	}
+}
@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+)
+
+func Test_GenerateGearTable(t *testing.T) {
+	table := fastcdc.GenerateGearTable([]byte("secret"))
+	if *table != *fastcdc.GenerateGearTable([]byte("secret")) {
+		t.Fatalf(`tables differ for the same seed`)
+	}
+	if *table == *fastcdc.GenerateGearTable([]byte("other secret")) {
+		t.Fatalf(`tables match for different seeds`)
+	}
+	// tables must not depend on the platform or the Go release
+	if table[0] != 0xaf25523a4a080047 || table[255] != 0xbb436ebc0f593803 {
+		t.Fatalf(`unexpected table entries %#x, %#x`, table[0], table[255])
+	}
+}
+
+func Test_GearTable(t *testing.T) {
+	data := rb[:16<<20]
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	public := splitLengths(t, "fastcdc", data, opts)
+
+	opts.Extension = &fastcdc.Options{GearTable: fastcdc.GenerateGearTable([]byte("secret"))}
+	private := splitLengths(t, "fastcdc", data, opts)
+	if slices.Equal(private, public) {
+		t.Fatalf(`the gear table has no effect`)
+	}
+
+	// a cooperating system deriving the same table cuts the same way
+	opts.Extension = &fastcdc.Options{GearTable: fastcdc.GenerateGearTable([]byte("secret"))}
+	for _, algorithm := range []string{"fastcdc", "fastcdc2020"} {
+		if lengths := splitLengths(t, algorithm, data, opts); !slices.Equal(lengths, private) {
+			t.Fatalf(`%s: boundaries differ with the same table`, algorithm)
+		}
+	}
+
+	opts.Extension = &struct{}{}
+	if err := chunkers.SetDefaultOptions("fastcdc", opts); !errors.Is(err, fastcdc.ErrOptions) {
+		t.Fatalf(`expected ErrOptions, got %v`, err)
+	}
+}
@@ -0,0 +1,15 @@
+module github.com/PlakarKorp/go-cdc-chunkers/tests
+
+go 1.23
+
+require (
+	codeberg.org/mhofmann/fastcdc v1.0.0
+	github.com/PlakarKorp/go-cdc-chunkers v0.0.0
+	github.com/askeladdk/fastcdc v0.0.2
+	github.com/jotfs/fastcdc-go v0.2.0
+	github.com/restic/chunker v0.4.0
+	github.com/tigerwill90/fastcdc v1.2.2
+)
+
+// the tests always exercise the core module they are shipped with
+replace github.com/PlakarKorp/go-cdc-chunkers => ../
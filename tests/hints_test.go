@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_ReadHints(t *testing.T) {
+	hints, err := chunkers.ReadHints(strings.NewReader("# tar members\n1536\n\n512\n 1536 \n"))
+	if err != nil {
+		t.Fatalf(`hints error: %s`, err)
+	}
+	if !slices.Equal(hints, []int64{512, 1536}) {
+		t.Fatalf(`unexpected hints %v`, hints)
+	}
+	if _, err := chunkers.ReadHints(strings.NewReader("512\n-3\n")); !errors.Is(err, chunkers.ErrHint) {
+		t.Fatalf(`expected ErrHint, got %v`, err)
+	}
+}
+
+func Test_Hints(t *testing.T) {
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	data := rb[:4<<20]
+	lengths := splitLengths(t, "fastcdc", data, opts)
+
+	// offset every cutpoint but the last by 100 bytes, alternating sides
+	var hints []int64
+	offset := int64(0)
+	for i, length := range lengths[:len(lengths)-1] {
+		offset += int64(length)
+		hints = append(hints, offset+int64(100*(1-2*(i%2))))
+	}
+
+	hinted := *opts
+	hinted.Hints = hints
+	hinted.HintTolerance = 128
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), &hinted)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	matched, cuts := 0, 0
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		cut := int64(offset + length)
+		_, found := slices.BinarySearch(hints, cut)
+		if found != (chunker.Flags() == chunkers.FlagHint) {
+			t.Fatalf(`cut at %d: hinted %v, flagged %d`, cut, found, chunker.Flags())
+		}
+		if found {
+			matched++
+		}
+		cuts++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	// moving a chunk start can move the next content-defined cutpoint
+	if matched < cuts*9/10 {
+		t.Fatalf(`only %d out of %d cutpoints snapped to a hint`, matched, cuts)
+	}
+
+	// hints beyond the tolerance are ignored
+	hinted.HintTolerance = 64
+	if !slices.Equal(splitLengths(t, "fastcdc", data, &hinted), lengths) {
+		t.Fatalf(`cutpoints moved to hints beyond the tolerance`)
+	}
+}
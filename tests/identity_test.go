@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/ultracdc"
+)
+
+func streamIdentity(t *testing.T, algorithm string, data []byte, opts *chunkers.ChunkerOpts, copy bool) []byte {
+	chunker, err := chunkers.NewChunker(algorithm, bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if copy {
+		chunker.Copy(io.Discard)
+	} else {
+		err = chunker.Split(func(offset, length uint64, chunk []byte) error { return nil })
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+	}
+	return chunker.StreamIdentity()
+}
+
+func Test_StreamIdentity(t *testing.T) {
+	data := rb[:4<<20]
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+
+	if streamIdentity(t, "fastcdc", data, opts, false) != nil {
+		t.Fatalf(`identity should be disabled by default`)
+	}
+
+	opts.StreamIdentity = true
+	reference := streamIdentity(t, "fastcdc", data, opts, false)
+	if len(reference) != 32 {
+		t.Fatalf(`expected a SHA-256 identity`)
+	}
+	if !bytes.Equal(reference, streamIdentity(t, "fastcdc", data, opts, true)) {
+		t.Fatalf(`Copy and Split should yield the same identity`)
+	}
+	if bytes.Equal(reference, streamIdentity(t, "ultracdc", data, opts, false)) {
+		t.Fatalf(`identity should be bound to the algorithm`)
+	}
+	if bytes.Equal(reference, streamIdentity(t, "fastcdc", data[1:], opts, false)) {
+		t.Fatalf(`identity should depend on the data`)
+	}
+
+	opts.MaxSize = 128 << 10
+	if bytes.Equal(reference, streamIdentity(t, "fastcdc", data, opts, false)) {
+		t.Fatalf(`identity should be bound to the options`)
+	}
+
+	ultra := streamIdentity(t, "ultracdc", data, opts, false)
+	opts.Extension = &ultracdc.Options{LowEntropyThreshold: 32, Pattern: 0xAA, Stride: 8}
+	if bytes.Equal(ultra, streamIdentity(t, "ultracdc", data, opts, false)) {
+		t.Fatalf(`identity should be bound to the algorithm's options`)
+	}
+}
+
+func Test_StreamIdentity_MaxLatency(t *testing.T) {
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, StreamIdentity: true, MaxLatency: time.Second}
+	_, err := chunkers.NewChunker("fastcdc", bytes.NewReader(rb[:1<<20]), opts)
+	if err != chunkers.ErrTimeCutIdentity {
+		t.Fatalf(`expected ErrTimeCutIdentity, got %v`, err)
+	}
+}
+
+// Extensions are identified by their contents rather than their
+// addresses, so that another process building the same options agrees on
+// identities and resumes states.
+func Test_StreamIdentity_Extension(t *testing.T) {
+	data := rb[:1<<20]
+	options := func(table *[256]uint64) *chunkers.ChunkerOpts {
+		opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, StreamIdentity: true}
+		opts.Extension = &fastcdc.Options{GearTable: table}
+		return opts
+	}
+
+	reference := streamIdentity(t, "fastcdc", data, options(fastcdc.GenerateGearTable([]byte("seed"))), false)
+	if !bytes.Equal(reference, streamIdentity(t, "fastcdc", data, options(fastcdc.GenerateGearTable([]byte("seed"))), false)) {
+		t.Fatalf(`identical tables should yield the same identity`)
+	}
+	if bytes.Equal(reference, streamIdentity(t, "fastcdc", data, options(fastcdc.GenerateGearTable([]byte("other"))), false)) {
+		t.Fatalf(`identity should be bound to the table`)
+	}
+
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), options(fastcdc.GenerateGearTable([]byte("seed"))))
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if _, err := chunker.Next(); err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	state, err := chunker.State()
+	if err != nil {
+		t.Fatalf(`state error: %s`, err)
+	}
+	offset, err := chunkers.ResumeOffset(state)
+	if err != nil {
+		t.Fatalf(`state error: %s`, err)
+	}
+	_, err = chunkers.ResumeChunker("fastcdc", bytes.NewReader(data[offset:]), state, options(fastcdc.GenerateGearTable([]byte("seed"))))
+	if err != nil {
+		t.Fatalf(`resume error: %s`, err)
+	}
+}
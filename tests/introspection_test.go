@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/maxp"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/rsync"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/winnowing"
+)
+
+func Test_MemoryFootprint(t *testing.T) {
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(rb[:1<<20]), nil)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if chunker.MemoryFootprint() != 2*chunker.MaxSize() {
+		t.Fatalf(`unexpected memory footprint %d`, chunker.MemoryFootprint())
+	}
+}
+
+func Test_Options(t *testing.T) {
+	defer chunkers.SetDefaultOptions("gear", nil)
+	defaults := &chunkers.ChunkerOpts{MinSize: 4 << 10, NormalSize: 16 << 10, MaxSize: 128 << 10}
+	if err := chunkers.SetDefaultOptions("gear", defaults); err != nil {
+		t.Fatalf(`options rejected: %s`, err)
+	}
+
+	for _, opts := range []*chunkers.ChunkerOpts{nil, {MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, NormalizationLevel: 3}} {
+		chunker, err := chunkers.NewChunker("gear", bytes.NewReader(rb[:1<<20]), opts)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		expected := defaults
+		if opts != nil {
+			expected = opts
+		}
+		if options := chunker.Options(); !reflect.DeepEqual(&options, expected) {
+			t.Fatalf(`options %+v, expected %+v`, options, *expected)
+		}
+		if chunker.AlgorithmName() != "gear@v1" {
+			t.Fatalf(`algorithm %q, expected gear@v1`, chunker.AlgorithmName())
+		}
+	}
+}
+
+// With no options passed, Options reports the defaults of the algorithm,
+// along with the values it selects for zero options.
+func Test_Options_Defaults(t *testing.T) {
+	for _, algorithm := range []string{"fastcdc", "fastcdc2020", "jc", "ultracdc", "gear", "bupsplit", "mii", "restic", "casync", "quickcdc", "rapidcdc", "sourcecode", "seqcdc", "pci", "maxp", "fixed", "winnowing", "rsync", "lbfs", "zstd-rsyncable"} {
+		chunker, err := chunkers.NewChunker(algorithm, bytes.NewReader(nil), nil)
+		if err != nil {
+			t.Fatalf(`%s: chunker error: %s`, algorithm, err)
+		}
+		options := chunker.Options()
+		expected, err := chunkers.DefaultOptionsFor(algorithm)
+		if err != nil {
+			t.Fatalf(`%s: options error: %s`, algorithm, err)
+		}
+		switch algorithm {
+		case "fastcdc", "fastcdc2020":
+			expected.Extension = &fastcdc.Options{NormalizationLevel: fastcdc.DefaultNormalizationLevel}
+		case "maxp":
+			expected.Extension = &maxp.Options{Window: (expected.NormalSize - expected.MinSize) * 2 / 3}
+		case "rsync":
+			expected.Extension = &rsync.Options{Window: rsync.DefaultWindow}
+		case "winnowing":
+			ext, ok := options.Extension.(*winnowing.Options)
+			if !ok || ext.Window == 0 {
+				t.Fatalf(`%s: window in effect not reported`, algorithm)
+			}
+			expected.Extension = ext
+		}
+		if !reflect.DeepEqual(&options, expected) {
+			t.Fatalf(`%s: options %+v, expected %+v`, algorithm, options, *expected)
+		}
+	}
+}
+
+func Test_Registry(t *testing.T) {
+	names := chunkers.List()
+	if !slices.IsSorted(names) || !slices.Contains(names, "fastcdc@v1") || !slices.Contains(names, "ultracdc@v1") {
+		t.Fatalf(`unexpected algorithms %v`, names)
+	}
+	if !slices.Equal(names, chunkers.Features().Algorithms) {
+		t.Fatalf(`algorithms %v listed, %v in features`, names, chunkers.Features().Algorithms)
+	}
+	if !chunkers.Exists("fastcdc") || !chunkers.Exists("fastcdc@v1") || !chunkers.Exists("fastcdc@v2") || chunkers.Exists("fastcdc@v3") || chunkers.Exists("unknown") {
+		t.Fatalf(`unexpected existence of algorithms`)
+	}
+
+	opts, err := chunkers.DefaultOptionsFor("ultracdc")
+	if err != nil {
+		t.Fatalf(`options error: %s`, err)
+	}
+	expected := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 10 << 10, MaxSize: 64 << 10}
+	if !reflect.DeepEqual(opts, expected) {
+		t.Fatalf(`options %+v, expected %+v`, *opts, *expected)
+	}
+
+	// those set by SetDefaultOptions included, copied
+	defer chunkers.SetDefaultOptions("ultracdc", nil)
+	if err := chunkers.SetDefaultOptions("ultracdc", &chunkers.ChunkerOpts{MinSize: 4 << 10, NormalSize: 16 << 10, MaxSize: 128 << 10}); err != nil {
+		t.Fatalf(`options rejected: %s`, err)
+	}
+	opts, err = chunkers.DefaultOptionsFor("ultracdc")
+	if err != nil {
+		t.Fatalf(`options error: %s`, err)
+	}
+	opts.MinSize = 0
+	if opts, _ := chunkers.DefaultOptionsFor("ultracdc"); opts.MinSize != 4<<10 || opts.NormalSize != 16<<10 {
+		t.Fatalf(`default options %+v`, *opts)
+	}
+	if _, err := chunkers.DefaultOptionsFor("unknown"); err == nil {
+		t.Fatalf(`unknown algorithm accepted`)
+	}
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func mean(lengths []int) float64 {
+	total := 0
+	for _, length := range lengths {
+		total += length
+	}
+	return float64(total) / float64(len(lengths))
+}
+
+func Test_Key(t *testing.T) {
+	data := rb[:4<<20]
+
+	for _, algorithm := range []string{"fastcdc", "fastcdc2020", "jc", "ultracdc", "gear", "restic", "casync", "quickcdc", "rapidcdc", "sourcecode", "winnowing", "lbfs"} {
+		opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+		public := splitLengths(t, algorithm, data, opts)
+		publicEntropy := entropy(t, algorithm, opts)
+
+		opts.Key = []byte("secret")
+		keyed := splitLengths(t, algorithm, data, opts)
+		if slices.Equal(keyed, public) {
+			t.Fatalf(`%s: the key has no effect`, algorithm)
+		}
+		if slices.Equal(entropy(t, algorithm, opts), publicEntropy) {
+			t.Fatalf(`%s: keyed constants reported as the public ones`, algorithm)
+		}
+		// perturbed constants keep the chunk size distribution
+		if m, k := mean(public), mean(keyed); k < 0.75*m || k > 1.25*m {
+			t.Fatalf(`%s: average chunk size moved from %.0f to %.0f once keyed`, algorithm, m, k)
+		}
+
+		if lengths := splitLengths(t, algorithm, data, opts); !slices.Equal(lengths, keyed) {
+			t.Fatalf(`%s: boundaries differ with the same key`, algorithm)
+		}
+		opts.Key = []byte("another secret")
+		if lengths := splitLengths(t, algorithm, data, opts); slices.Equal(lengths, keyed) {
+			t.Fatalf(`%s: boundaries match for different keys`, algorithm)
+		}
+	}
+}
+
+func Test_Key_Unkeyed(t *testing.T) {
+	for _, algorithm := range []string{"bupsplit", "mii", "seqcdc", "pci", "maxp", "fixed", "rsync", "zstd-rsyncable"} {
+		opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, Key: []byte("secret")}
+		if _, err := chunkers.NewChunker(algorithm, bytes.NewReader(nil), opts); err != chunkers.ErrUnkeyed {
+			t.Fatalf(`%s: expected ErrUnkeyed, got %v`, algorithm, err)
+		}
+		if err := chunkers.SetDefaultOptions(algorithm, opts); err != chunkers.ErrUnkeyed {
+			t.Fatalf(`%s: expected ErrUnkeyed, got %v`, algorithm, err)
+		}
+	}
+}
+
+func Test_Key_StreamIdentity(t *testing.T) {
+	data := rb[:1<<20]
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, StreamIdentity: true}
+
+	identity := func(key []byte) []byte {
+		opts.Key = key
+		chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		if err := chunker.Split(func(offset, length uint64, chunk []byte) error { return nil }); err != nil {
+			t.Fatalf(`split error: %s`, err)
+		}
+		return chunker.StreamIdentity()
+	}
+
+	keyed := identity([]byte("secret"))
+	if bytes.Equal(keyed, identity(nil)) || bytes.Equal(keyed, identity([]byte("another secret"))) {
+		t.Fatalf(`the stream identity is not bound to the key`)
+	}
+	if !bytes.Equal(keyed, identity([]byte("secret"))) {
+		t.Fatalf(`the stream identity differs with the same key`)
+	}
+}
@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/bupsplit"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/casync"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/rsync"
+)
+
+// media and disk images are archived with averages of several MiB
+var largeAverages = []int{4 << 20, 16 << 20}
+
+// largeAlgorithms leaves out algorithms whose per-byte cost would make
+// the test last minutes, and those refusing large averages
+var largeAlgorithms = []string{"fastcdc@v2", "fastcdc2020@v2", "jc@v2", "ultracdc@v2", "gear", "restic", "quickcdc", "rapidcdc", "sourcecode", "seqcdc", "pci", "fixed", "lbfs", "zstd-rsyncable"}
+
+func largeOpts(average int) *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{MinSize: average / 4, NormalSize: average, MaxSize: 4 * average}
+}
+
+func Test_LargeChunks(t *testing.T) {
+	data := rb[:512<<20]
+
+	// algorithms aiming at NormalSize keep doing so, where the masks of
+	// fastcdc and jc v1 cut shortly after MinSize
+	aiming := []string{"fastcdc@v2", "fastcdc2020@v2", "jc@v2", "seqcdc", "quickcdc", "rapidcdc", "restic", "lbfs", "zstd-rsyncable"}
+
+	for _, average := range largeAverages {
+		for _, algorithm := range largeAlgorithms {
+			opts := largeOpts(average)
+			opts.BorrowBuffers = true
+			chunker, err := chunkers.NewChunker(algorithm, bytes.NewReader(data), opts)
+			if err != nil {
+				t.Fatalf(`%s: chunker error: %s`, algorithm, err)
+			}
+
+			var lengths []int
+			forced := 0
+			for {
+				chunk, err := chunker.Next()
+				if err != nil && err != io.EOF {
+					t.Fatalf(`%s: chunker error: %s`, algorithm, err)
+				}
+				if len(chunk) != 0 {
+					lengths = append(lengths, len(chunk))
+				}
+				if chunker.Flags()&chunkers.FlagForced != 0 {
+					forced++
+				}
+				if err == io.EOF {
+					break
+				}
+			}
+			if ratio := float64(forced) / float64(len(lengths)); ratio > 0.1 {
+				t.Fatalf(`%s: %.1f%% of %dMiB chunks forced`, algorithm, 100*ratio, average>>20)
+			}
+			if m := mean(lengths); slices.Contains(aiming, algorithm) && (m < 0.5*float64(average) || m > 1.5*float64(average)) {
+				t.Fatalf(`%s: average chunk size of %.2fMiB for a NormalSize of %dMiB`, algorithm, m/(1<<20), average>>20)
+			}
+		}
+	}
+
+	// rolling sums that cannot reach such averages refuse them rather
+	// than cutting every chunk at MaxSize
+	for _, test := range []struct {
+		implementation chunkers.ChunkerImplementation
+		err            error
+	}{
+		{&bupsplit.BupSplit{}, bupsplit.ErrNormalSize},
+		{&rsync.Rsync{}, rsync.ErrNormalSize},
+		{&casync.Casync{}, casync.ErrNormalSize},
+	} {
+		if err := test.implementation.Validate(largeOpts(16 << 20)); !errors.Is(err, test.err) {
+			t.Fatalf(`%T: expected %v, got %v`, test.implementation, test.err, err)
+		}
+	}
+}
+
+func Benchmark_LargeChunks(b *testing.B) {
+	data := rb[:256<<20]
+
+	for _, average := range largeAverages {
+		for _, algorithm := range largeAlgorithms {
+			b.Run(fmt.Sprintf("%s/%dMiB", algorithm, average>>20), func(b *testing.B) {
+				opts := largeOpts(average)
+				opts.BorrowBuffers = true
+				r := bytes.NewReader(data)
+				b.SetBytes(int64(len(data)))
+				b.ResetTimer()
+				nchunks := 0
+				for i := 0; i < b.N; i++ {
+					chunker, err := chunkers.NewChunker(algorithm, r, opts)
+					if err != nil {
+						b.Fatalf(`chunker error: %s`, err)
+					}
+					err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+						nchunks++
+						return nil
+					})
+					if err != nil {
+						b.Fatalf(`chunker error: %s`, err)
+					}
+					r.Reset(data)
+				}
+				b.ReportMetric(float64(nchunks)/float64(b.N), "chunks")
+			})
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_MaxLatency(t *testing.T) {
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, MaxLatency: 50 * time.Millisecond}
+	data := rb[:1<<20]
+
+	r, w := io.Pipe()
+	go func() {
+		// a burst too short to hold a cutpoint, then a pause
+		w.Write(data[:1000])
+		time.Sleep(time.Second)
+		w.Write(data[1000:])
+		w.Close()
+	}()
+
+	chunker, err := chunkers.NewChunker("fastcdc", r, opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	start := time.Now()
+	chunk, err := chunker.Next()
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf(`first chunk took %s`, elapsed)
+	}
+	if len(chunk) != 1000 || chunker.Flags() != chunkers.FlagTimeCut {
+		t.Fatalf(`expected a time cut of 1000 bytes, got %d bytes flagged %d`, len(chunk), chunker.Flags())
+	}
+
+	total := len(chunk)
+	for err == nil {
+		chunk, err = chunker.Next()
+		if err != nil && err != io.EOF {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		total += len(chunk)
+	}
+	if total != len(data) {
+		t.Fatalf(`expected %d bytes, got %d`, len(data), total)
+	}
+	if stats := chunker.CutStats(); stats.TimeCuts != 1 {
+		t.Fatalf(`expected a single time cut, got %+v`, stats)
+	}
+}
+
+func Test_MaxLatency_Split(t *testing.T) {
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, MaxLatency: time.Second}
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(rb[:1<<20]), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	defer chunker.Close()
+
+	total := uint64(0)
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		total += length
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if total != 1<<20 {
+		t.Fatalf(`expected %d bytes, got %d`, 1<<20, total)
+	}
+}
+
+func Test_MaxLatency_Close(t *testing.T) {
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, MaxLatency: time.Second}
+
+	// nothing is ever written, Next blocks until the chunker is closed
+	r, w := io.Pipe()
+	defer w.Close()
+	chunker, err := chunkers.NewChunker("fastcdc", r, opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	result := make(chan error)
+	go func() {
+		_, err := chunker.Next()
+		result <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	chunker.Close()
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, chunkers.ErrClosed) {
+			t.Fatalf(`expected ErrClosed, got %v`, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf(`Next did not return once the chunker was closed`)
+	}
+}
@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func chunkLevels(t *testing.T, algorithm string, opts *chunkers.ChunkerOpts) map[int]int {
+	chunker, err := chunkers.NewChunker(algorithm, bytes.NewReader(rb[:16<<20]), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	levels := make(map[int]int)
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		levels[chunker.Level()]++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	return levels
+}
+
+func Test_Level(t *testing.T) {
+	if levels := chunkLevels(t, "fastcdc", nil); len(levels) != 1 || levels[0] == 0 {
+		t.Fatalf(`fastcdc should not rank its cutpoints: %v`, levels)
+	}
+
+	// each level is about 16 times rarer than the one below it
+	levels := chunkLevels(t, "bupsplit", nil)
+	if levels[1] == 0 || levels[0] < 8*levels[1] {
+		t.Fatalf(`unexpected bupsplit levels %v`, levels)
+	}
+
+	// hints move cutpoints away from the algorithm's choice
+	opts := &chunkers.ChunkerOpts{MinSize: 0, NormalSize: 8 << 10, MaxSize: 32 << 10, HintTolerance: 1 << 20}
+	for offset := int64(0); offset < 16<<20; offset += 4 << 10 {
+		opts.Hints = append(opts.Hints, offset)
+	}
+	if levels := chunkLevels(t, "bupsplit", opts); len(levels) != 1 {
+		t.Fatalf(`hinted cutpoints should not carry levels: %v`, levels)
+	}
+}
@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+)
+
+func stddev(lengths []int) float64 {
+	var sum, squares float64
+	for _, length := range lengths {
+		sum += float64(length)
+		squares += float64(length) * float64(length)
+	}
+	mean := sum / float64(len(lengths))
+	return math.Sqrt(squares/float64(len(lengths)) - mean*mean)
+}
+
+// Higher normalization levels narrow the chunk size distribution.
+func Test_NormalizationLevel(t *testing.T) {
+	opts := func(level int) *chunkers.ChunkerOpts {
+		return &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, NormalizationLevel: level}
+	}
+
+	data := rb[:64<<20]
+	if !slices.Equal(splitLengths(t, "fastcdc", data, opts(0)), splitLengths(t, "fastcdc", data, opts(fastcdc.DefaultNormalizationLevel))) {
+		t.Fatalf(`level 0 does not select the default level`)
+	}
+
+	previous := math.Inf(1)
+	for _, level := range []int{-1, 1, 2, 3} {
+		deviation := stddev(splitLengths(t, "fastcdc", data, opts(level)))
+		if deviation >= previous {
+			t.Fatalf(`level %d: standard deviation %f did not decrease from %f`, level, deviation, previous)
+		}
+		previous = deviation
+	}
+
+	for _, level := range []int{-2, 4} {
+		if err := (&fastcdc.FastCDC{}).Validate(opts(level)); err != fastcdc.ErrNormalizationLevel {
+			t.Fatalf(`level %d: expected ErrNormalizationLevel, got %v`, level, err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"io"
+	"slices"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+// repeated reads as data repeated forever, standing in for streams larger
+// than what tests can hold in memory
+type repeated struct {
+	data []byte
+}
+
+func (r repeated) ReadAt(p []byte, off int64) (int, error) {
+	n := 0
+	for n < len(p) {
+		n += copy(p[n:], r.data[(off+int64(n))%int64(len(r.data)):])
+	}
+	return n, nil
+}
+
+// Offsets past 4GiB are reported as they are, where uint offsets used to
+// wrap around on 32-bit platforms.
+func Test_Offsets_Large(t *testing.T) {
+	size := int64(4<<30 + 12345)
+	for _, algorithm := range []string{"fixed", "fastcdc"} {
+		r := io.NewSectionReader(repeated{rb[:16<<20]}, 0, size)
+		opts := &chunkers.ChunkerOpts{MinSize: 256 << 10, NormalSize: 1 << 20, MaxSize: 4 << 20, BorrowBuffers: true}
+		chunker, err := chunkers.NewChunker(algorithm, r, opts)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+
+		expected := uint64(0)
+		err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+			if offset != expected {
+				t.Fatalf(`%s: chunk at offset %d, expected %d`, algorithm, offset, expected)
+			}
+			expected += length
+			return nil
+		})
+		if err != nil {
+			t.Fatalf(`%s: chunker error: %s`, algorithm, err)
+		}
+		if expected != uint64(size) {
+			t.Fatalf(`%s: chunked %d bytes, expected %d`, algorithm, expected, size)
+		}
+	}
+}
+
+func Test_Offsets_Anchor(t *testing.T) {
+	anchor := int64(5 << 30)
+	r := io.NewSectionReader(repeated{rb[:16<<20]}, 0, anchor+16<<20)
+
+	var tagged []uint64
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	opts.Tagger = func(offset, length uint64, chunk []byte) map[string]string {
+		tagged = append(tagged, offset)
+		return nil
+	}
+	chunker, err := chunkers.NewChunkerAt("fastcdc", r, anchor, opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	var offsets []uint64
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		offsets = append(offsets, offset)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if offsets[0] != uint64(anchor) {
+		t.Fatalf(`first chunk at offset %d, expected %d`, offsets[0], anchor)
+	}
+	if !slices.Equal(tagged, offsets) {
+		t.Fatalf(`Tagger and Split disagree on offsets`)
+	}
+}
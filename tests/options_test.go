@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/maxp"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/restic"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/rsync"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/ultracdc"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/winnowing"
+)
+
+func Test_NewOptions(t *testing.T) {
+	opts, err := chunkers.NewOptions("fastcdc", chunkers.WithMinSize(4<<10), chunkers.WithNormalization(3), chunkers.WithKey([]byte("key")))
+	if err != nil {
+		t.Fatalf(`options rejected: %s`, err)
+	}
+	// fields left unset keep the defaults of the algorithm
+	expected := &chunkers.ChunkerOpts{MinSize: 4 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, NormalizationLevel: 3, Key: []byte("key")}
+	if !reflect.DeepEqual(opts, expected) {
+		t.Fatalf(`got %+v, expected %+v`, opts, expected)
+	}
+
+	// as set by SetDefaultOptions
+	defer chunkers.SetDefaultOptions("fastcdc", nil)
+	if err := chunkers.SetDefaultOptions("fastcdc", &chunkers.ChunkerOpts{MinSize: 4 << 10, NormalSize: 16 << 10, MaxSize: 128 << 10}); err != nil {
+		t.Fatalf(`options rejected: %s`, err)
+	}
+	opts, err = chunkers.NewOptions("fastcdc", chunkers.WithMaxSize(256<<10))
+	if err != nil {
+		t.Fatalf(`options rejected: %s`, err)
+	}
+	if opts.MinSize != 4<<10 || opts.NormalSize != 16<<10 || opts.MaxSize != 256<<10 {
+		t.Fatalf(`default options not applied: %+v`, opts)
+	}
+
+	// and validated
+	if _, err := chunkers.NewOptions("fastcdc", chunkers.WithMinSize(32<<10)); !errors.Is(err, fastcdc.ErrMinSize) {
+		t.Fatalf(`expected ErrMinSize, got %v`, err)
+	}
+	if _, err := chunkers.NewOptions("fixed", chunkers.WithKey([]byte("key"))); err != chunkers.ErrUnkeyed {
+		t.Fatalf(`expected ErrUnkeyed, got %v`, err)
+	}
+	if _, err := chunkers.NewOptions("unknown"); err == nil {
+		t.Fatalf(`unknown algorithm accepted`)
+	}
+}
+
+func Test_NewOptions_Extension(t *testing.T) {
+	// options of an algorithm build on each other, and on an extension
+	// already set, which is copied rather than modified
+	shared := ultracdc.NewOptions()
+	opts, err := chunkers.NewOptions("ultracdc", chunkers.WithExtension(shared), ultracdc.WithStride(4), ultracdc.WithLowEntropyThreshold(32))
+	if err != nil {
+		t.Fatalf(`options rejected: %s`, err)
+	}
+	expected := &ultracdc.Options{LowEntropyThreshold: 32, Pattern: 0xAA, Stride: 4}
+	if !reflect.DeepEqual(opts.Extension, expected) {
+		t.Fatalf(`got extension %+v, expected %+v`, opts.Extension, expected)
+	}
+	if !reflect.DeepEqual(shared, ultracdc.NewOptions()) {
+		t.Fatalf(`shared extension modified`)
+	}
+	if _, err := chunkers.NewOptions("ultracdc", ultracdc.WithStride(9)); !errors.Is(err, ultracdc.ErrOptions) {
+		t.Fatalf(`expected ErrOptions, got %v`, err)
+	}
+
+	table := fastcdc.GenerateGearTable([]byte("seed"))
+	opts, err = chunkers.NewOptions("fastcdc2020", fastcdc.WithGearTable(table))
+	if err != nil {
+		t.Fatalf(`options rejected: %s`, err)
+	}
+	if ext, ok := opts.Extension.(*fastcdc.Options); !ok || ext.GearTable != table {
+		t.Fatalf(`gear table not set`)
+	}
+}
+
+// The options of an algorithm cut as the deprecated fields of ChunkerOpts
+// they replace, and take precedence over them.
+func Test_NewOptions_Deprecated(t *testing.T) {
+	polynomial, err := restic.RandomPolynomial()
+	if err != nil {
+		t.Fatalf(`polynomial error: %s`, err)
+	}
+	for _, test := range []struct {
+		algorithm  string
+		option     chunkers.Option
+		deprecated func(*chunkers.ChunkerOpts)
+		overridden func(*chunkers.ChunkerOpts)
+	}{
+		{"fastcdc", fastcdc.WithNormalization(3), func(opts *chunkers.ChunkerOpts) { opts.NormalizationLevel = 3 }, func(opts *chunkers.ChunkerOpts) { opts.NormalizationLevel = 1 }},
+		{"windowless-gear", chunkers.WithWindowlessNormalization(3), func(opts *chunkers.ChunkerOpts) { opts.NormalizationLevel = 3 }, func(opts *chunkers.ChunkerOpts) { opts.NormalizationLevel = 1 }},
+		{"lbfs", restic.WithPolynomial(polynomial), func(opts *chunkers.ChunkerOpts) { opts.Polynomial = polynomial }, func(opts *chunkers.ChunkerOpts) { opts.Polynomial = restic.LBFSPolynomial }},
+		{"maxp", maxp.WithWindow(1000), func(opts *chunkers.ChunkerOpts) { opts.Window = 1000 }, func(opts *chunkers.ChunkerOpts) { opts.Window = 3000 }},
+		{"winnowing", winnowing.WithWindow(1000), func(opts *chunkers.ChunkerOpts) { opts.Window = 1000 }, func(opts *chunkers.ChunkerOpts) { opts.Window = 3000 }},
+		{"rsync", rsync.WithWindow(128), func(opts *chunkers.ChunkerOpts) { opts.Window = 128 }, func(opts *chunkers.ChunkerOpts) { opts.Window = 32 }},
+	} {
+		data := rb[:4<<20]
+		opts, err := chunkers.NewOptions(test.algorithm, test.option)
+		if err != nil {
+			t.Fatalf(`%s: options rejected: %s`, test.algorithm, err)
+		}
+		expected := splitLengths(t, test.algorithm, data, opts)
+
+		deprecated, err := chunkers.NewOptions(test.algorithm, test.deprecated)
+		if err != nil {
+			t.Fatalf(`%s: options rejected: %s`, test.algorithm, err)
+		}
+		if !slices.Equal(splitLengths(t, test.algorithm, data, deprecated), expected) {
+			t.Fatalf(`%s: deprecated field cuts differently`, test.algorithm)
+		}
+
+		overridden, err := chunkers.NewOptions(test.algorithm, test.overridden)
+		if err != nil {
+			t.Fatalf(`%s: options rejected: %s`, test.algorithm, err)
+		}
+		if slices.Equal(splitLengths(t, test.algorithm, data, overridden), expected) {
+			t.Fatalf(`%s: option has no effect`, test.algorithm)
+		}
+		test.option(overridden)
+		if !slices.Equal(splitLengths(t, test.algorithm, data, overridden), expected) {
+			t.Fatalf(`%s: option does not take precedence over the deprecated field`, test.algorithm)
+		}
+	}
+
+	if _, err := chunkers.NewOptions("maxp", maxp.WithWindow(-1)); !errors.Is(err, maxp.ErrWindow) {
+		t.Fatalf(`expected ErrWindow, got %v`, err)
+	}
+	if _, err := chunkers.NewOptions("fastcdc", fastcdc.WithNormalization(4)); !errors.Is(err, fastcdc.ErrNormalizationLevel) {
+		t.Fatalf(`expected ErrNormalizationLevel, got %v`, err)
+	}
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_Peek(t *testing.T) {
+	data := rb[:1<<20]
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	expected := cuts(t, chunker)
+
+	chunker, err = chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	var offsets []uint64
+	for {
+		// peeking twice does not consume the chunk
+		peeked, perr := chunker.Peek()
+		again, _ := chunker.Peek()
+		chunk, err := chunker.NextChunk()
+		if !bytes.Equal(peeked, again) || !bytes.Equal(peeked, chunk.Data) || err != perr {
+			t.Fatalf(`chunk at %d differs from the one peeked`, chunk.Offset)
+		}
+		if len(chunk.Data) != 0 {
+			offsets = append(offsets, uint64(chunk.Offset))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+	}
+	if !slices.Equal(offsets, expected) {
+		t.Fatalf(`peeking changed cutpoints`)
+	}
+
+	// a peeked chunk is not consumed yet
+	chunker, err = chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if _, err := chunker.Peek(); err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if _, err := chunker.State(); err != chunkers.ErrStatePeeked {
+		t.Fatalf(`got %v, expected %v`, err, chunkers.ErrStatePeeked)
+	}
+}
+
+func Test_Skip(t *testing.T) {
+	data := rb[:1<<20]
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	expected := cuts(t, chunker)
+
+	// Split carries on at the offset of the chunk after those skipped,
+	// a peeked one included
+	chunker, err = chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if _, err := chunker.Peek(); err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if skipped, err := chunker.Skip(10); skipped != 10 || err != nil {
+		t.Fatalf(`skipped %d chunks: %v`, skipped, err)
+	}
+	if offsets := cuts(t, chunker); !slices.Equal(offsets, expected[10:]) {
+		t.Fatalf(`chunks after those skipped cut at other offsets`)
+	}
+
+	// fewer chunks are skipped past the end of the stream
+	chunker, err = chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if skipped, err := chunker.Skip(len(expected) + 1); skipped != len(expected) || err != io.EOF {
+		t.Fatalf(`skipped %d chunks out of %d: %v`, skipped, len(expected), err)
+	}
+}
@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_CopyQueue(t *testing.T) {
+	data := rb[:16<<20]
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+
+	var expected [][]byte
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	copied, err := chunker.Copy(writerFunc(func(p []byte) (int, error) {
+		expected = append(expected, bytes.Clone(p))
+		return len(p), nil
+	}))
+	if err != io.EOF {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	for _, depth := range []int{1, 4} {
+		var writes [][]byte
+		chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		n, err := chunker.CopyQueue(writerFunc(func(p []byte) (int, error) {
+			writes = append(writes, bytes.Clone(p))
+			return len(p), nil
+		}), depth)
+		if err != io.EOF {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		if n != copied {
+			t.Fatalf(`depth %d: copied %d bytes, Copy reported %d`, depth, n, copied)
+		}
+		if len(writes) != len(expected) {
+			t.Fatalf(`depth %d: expected %d writes, got %d`, depth, len(expected), len(writes))
+		}
+		for i := range writes {
+			if !bytes.Equal(writes[i], expected[i]) {
+				t.Fatalf(`depth %d: write %d out of order`, depth, i)
+			}
+		}
+	}
+
+	if _, err := chunker.CopyQueue(io.Discard, 0); err != chunkers.ErrQueueDepth {
+		t.Fatalf(`expected ErrQueueDepth, got %v`, err)
+	}
+}
+
+type countingReader struct {
+	r    io.Reader
+	read atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.read.Add(int64(n))
+	return n, err
+}
+
+// A stalled writer stops chunking once the queue is full.
+func Test_CopyQueue_FlowControl(t *testing.T) {
+	const depth = 4
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	r := &countingReader{r: bytes.NewReader(rb[:64<<20])}
+	chunker, err := chunkers.NewChunker("fastcdc", r, opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	release := make(chan struct{})
+	result := make(chan error)
+	go func() {
+		_, err := chunker.CopyQueue(writerFunc(func(p []byte) (int, error) {
+			<-release
+			return len(p), nil
+		}), depth)
+		result <- err
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	// depth queued chunks, one being written, and the chunker's buffer
+	if read := r.read.Load(); read > (depth+1+2)*int64(opts.MaxSize) {
+		t.Fatalf(`%d bytes read ahead of a stalled writer`, read)
+	}
+	close(release)
+	if err := <-result; err != io.EOF {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+}
+
+func Test_CopyQueue_WriteError(t *testing.T) {
+	failure := errors.New("disk full")
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(rb[:16<<20]), nil)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	writes := 0
+	_, err = chunker.CopyQueue(writerFunc(func(p []byte) (int, error) {
+		writes++
+		if writes == 3 {
+			return 0, failure
+		}
+		return len(p), nil
+	}), 2)
+	if err != failure {
+		t.Fatalf(`expected the write error, got %v`, err)
+	}
+	if writes != 3 {
+		t.Fatalf(`%d writes after a failure`, writes-3)
+	}
+}
+
+type offsetWriter struct {
+	offsets []uint64
+	lengths []uint64
+}
+
+func (w *offsetWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("Write called on an OffsetWriter")
+}
+
+func (w *offsetWriter) WriteChunk(offset uint64, p []byte) (int, error) {
+	w.offsets = append(w.offsets, uint64(offset))
+	w.lengths = append(w.lengths, uint64(len(p)))
+	return len(p), nil
+}
+
+func Test_Copy_OffsetWriter(t *testing.T) {
+	data := rb[:4<<20]
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	const anchor = 1 << 20
+
+	var offsets, lengths []uint64
+	chunker, err := chunkers.NewChunkerAt("fastcdc", bytes.NewReader(data), anchor, opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		offsets = append(offsets, offset)
+		lengths = append(lengths, length)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	for _, depth := range []int{0, 4} {
+		w := &offsetWriter{}
+		chunker, err := chunkers.NewChunkerAt("fastcdc", bytes.NewReader(data), anchor, opts)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		if depth == 0 {
+			_, err = chunker.Copy(w)
+		} else {
+			_, err = chunker.CopyQueue(w, depth)
+		}
+		if err != io.EOF {
+			t.Fatalf(`depth %d: chunker error: %s`, depth, err)
+		}
+		if !slices.Equal(w.offsets, offsets) || !slices.Equal(w.lengths, lengths) {
+			t.Fatalf(`depth %d: chunks written at other offsets than split`, depth)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/gear"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/ultracdc"
+)
+
+func newTestGear() chunkers.ChunkerImplementation {
+	return &gear.Gear{}
+}
+
+func Test_Unregister(t *testing.T) {
+	if err := chunkers.Register("test-unregistered", newTestGear); err != nil {
+		t.Fatalf(`register error: %s`, err)
+	}
+	chunkers.MustRegister("test-unregistered@v2", func() chunkers.ChunkerImplementation { return &ultracdc.UltraCDC{} })
+	if err := chunkers.SetDefaultOptions("test-unregistered", &chunkers.ChunkerOpts{MinSize: 4 << 10, NormalSize: 16 << 10, MaxSize: 128 << 10}); err != nil {
+		t.Fatalf(`options rejected: %s`, err)
+	}
+	chunker, err := chunkers.NewChunker("test-unregistered", bytes.NewReader(rb[:1<<20]), nil)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	// the bare name now resolves to the version left, chunkers created
+	// before keep running
+	if err := chunkers.Unregister("test-unregistered"); err != nil {
+		t.Fatalf(`unregister error: %s`, err)
+	}
+	if resolved, err := chunkers.Resolve("test-unregistered"); err != nil || resolved != "test-unregistered@v2" {
+		t.Fatalf(`resolved to %q: %v`, resolved, err)
+	}
+	chunker.Reset(bytes.NewReader(rb[:1<<20]))
+	if _, err := chunker.Next(); err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if err := chunkers.Unregister("test-unregistered@v1"); err == nil {
+		t.Fatalf(`unregistered twice`)
+	}
+	if err := chunkers.Unregister("test-unregistered@v2"); err != nil {
+		t.Fatalf(`unregister error: %s`, err)
+	}
+	if chunkers.Exists("test-unregistered") {
+		t.Fatalf(`unregistered algorithm exists`)
+	}
+
+	// registered again, without the options set before
+	chunkers.MustRegister("test-unregistered", newTestGear)
+	defer chunkers.Unregister("test-unregistered")
+	if opts, err := chunkers.DefaultOptionsFor("test-unregistered"); err != nil || opts.MinSize == 4<<10 {
+		t.Fatalf(`options %+v kept after Unregister: %v`, opts, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf(`registered twice without panicking`)
+		}
+	}()
+	chunkers.MustRegister("test-unregistered", newTestGear)
+}
+
+// Plugins may register algorithms while others are in use.
+func Test_Register_Concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name := fmt.Sprintf("test-concurrent-%d", i)
+			for j := 0; j < 100; j++ {
+				if err := chunkers.Register(name, newTestGear); err != nil {
+					t.Errorf(`register error: %s`, err)
+					return
+				}
+				if _, err := chunkers.NewChunker("fastcdc", bytes.NewReader(rb[:64<<10]), nil); err != nil {
+					t.Errorf(`chunker error: %s`, err)
+					return
+				}
+				chunkers.List()
+				if err := chunkers.Unregister(name); err != nil {
+					t.Errorf(`unregister error: %s`, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_Reset(t *testing.T) {
+	files := [][]byte{rb[:1<<20], rb[3<<20 : 3<<20+300<<10], rb[5<<20 : 5<<20+100]}
+
+	for _, algorithm := range []string{"fastcdc", "quickcdc", "test-stateful"} {
+		opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, StreamIdentity: true}
+		var chunker *chunkers.Chunker
+		for i, file := range files {
+			if chunker == nil {
+				// offsets restart at zero, even after an anchor
+				var err error
+				if algorithm == "fastcdc" {
+					chunker, err = chunkers.NewChunkerAt(algorithm, bytes.NewReader(rb), 7<<20, opts)
+				} else {
+					chunker, err = chunkers.NewChunker(algorithm, bytes.NewReader(rb), opts)
+				}
+				if err != nil {
+					t.Fatalf(`chunker error: %s`, err)
+				}
+				// abandon the first stream midway
+				if _, err := chunker.Next(); err != nil {
+					t.Fatalf(`chunker error: %s`, err)
+				}
+			}
+			chunker.Reset(bytes.NewReader(file))
+
+			var offsets []uint64
+			err := chunker.Split(func(offset, length uint64, chunk []byte) error {
+				offsets = append(offsets, offset)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf(`chunker error: %s`, err)
+			}
+
+			fresh, err := chunkers.NewChunker(algorithm, bytes.NewReader(file), opts)
+			if err != nil {
+				t.Fatalf(`chunker error: %s`, err)
+			}
+			var expected []uint64
+			err = fresh.Split(func(offset, length uint64, chunk []byte) error {
+				expected = append(expected, offset)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf(`chunker error: %s`, err)
+			}
+
+			if !slices.Equal(offsets, expected) {
+				t.Fatalf(`%s: file %d cut at %v after Reset, %v by a new chunker`, algorithm, i, offsets, expected)
+			}
+			if !bytes.Equal(chunker.StreamIdentity(), fresh.StreamIdentity()) {
+				t.Fatalf(`%s: file %d has another identity after Reset`, algorithm, i)
+			}
+			if chunker.CutStats() != fresh.CutStats() {
+				t.Fatalf(`%s: file %d has cut stats %+v after Reset, %+v by a new chunker`, algorithm, i, chunker.CutStats(), fresh.CutStats())
+			}
+		}
+	}
+}
+
+// Chunking many small files through one chunker only allocates once.
+func Test_Reset_Allocs(t *testing.T) {
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, BorrowBuffers: true}
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(nil), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	file := rb[:20<<10]
+	r := bytes.NewReader(file)
+	callback := func(offset, length uint64, chunk []byte) error {
+		return nil
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		r.Reset(file)
+		chunker.Reset(r)
+		if err := chunker.Split(callback); err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf(`%f allocations per file`, allocs)
+	}
+}
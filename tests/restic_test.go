@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	cdcrestic "github.com/PlakarKorp/go-cdc-chunkers/chunkers/restic"
+	restic "github.com/restic/chunker"
+)
+
+func Test_Restic_Compatibility(t *testing.T) {
+	polynomial, err := cdcrestic.RandomPolynomial()
+	if err != nil {
+		t.Fatalf(`RandomPolynomial error: %s`, err)
+	}
+
+	data := rb[:64<<20]
+	for _, opts := range []*chunkers.ChunkerOpts{
+		{MinSize: 512 << 10, NormalSize: 1 << 20, MaxSize: 8 << 20, Polynomial: polynomial},
+		{MinSize: 64 << 10, NormalSize: 256 << 10, MaxSize: 1 << 20, Polynomial: polynomial},
+		{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10},
+	} {
+		var expected []uint64
+		pol := restic.Pol(opts.Polynomial)
+		if pol == 0 {
+			pol = cdcrestic.DefaultPolynomial
+		}
+		reference := restic.NewWithBoundaries(bytes.NewReader(data), pol, uint(opts.MinSize), uint(opts.MaxSize))
+		reference.SetAverageBits(bits(opts.NormalSize))
+		buffer := make([]byte, opts.MaxSize)
+		for {
+			chunk, err := reference.Next(buffer)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf(`restic error: %s`, err)
+			}
+			expected = append(expected, uint64(chunk.Length))
+		}
+
+		var lengths []uint64
+		chunker, err := chunkers.NewChunker("restic", bytes.NewReader(data), opts)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+			lengths = append(lengths, length)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+
+		if len(lengths) != len(expected) {
+			t.Fatalf(`%v: expected %d chunks, got %d`, opts, len(expected), len(lengths))
+		}
+		for i := range expected {
+			if lengths[i] != expected[i] {
+				t.Fatalf(`%v: chunk %d: expected length %d, got %d`, opts, i, expected[i], lengths[i])
+			}
+		}
+	}
+}
+
+func bits(size int) int {
+	n := 0
+	for ; size > 1; size >>= 1 {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_SelfBench(t *testing.T) {
+	for _, name := range []string{"fastcdc", "jc", "ultracdc"} {
+		result, err := chunkers.SelfBench(name, nil, 0)
+		if err != nil {
+			t.Fatalf(`selfbench error: %s`, err)
+		}
+		if result.Bytes == 0 || result.Chunks == 0 {
+			t.Fatalf(`selfbench for %s chunked nothing`, name)
+		}
+		if result.GBps <= 0 || result.ChunksPerSec <= 0 {
+			t.Fatalf(`selfbench for %s reported no throughput`, name)
+		}
+	}
+
+	if _, err := chunkers.SelfBench("unknown", nil, 0); err == nil {
+		t.Fatalf(`selfbench accepted an unknown algorithm`)
+	}
+}
+
+func Test_BenchCorpus(t *testing.T) {
+	// the same 8MiB twice: the second half deduplicates entirely
+	half := rb[:8<<20]
+	data := append(append([]byte{}, half...), half...)
+
+	result, err := chunkers.BenchCorpus("fastcdc", nil, "twice", data, 0)
+	if err != nil {
+		t.Fatalf(`bench error: %s`, err)
+	}
+	if result.Corpus != "twice" || result.Passes != 1 {
+		t.Fatalf(`unexpected result %+v`, result)
+	}
+	if result.DedupRatio < 0.45 || result.DedupRatio > 0.5 {
+		t.Fatalf(`expected a dedup ratio close to 0.5, got %f`, result.DedupRatio)
+	}
+
+	var line bytes.Buffer
+	if err := result.WriteBenchfmt(&line); err != nil {
+		t.Fatalf(`benchfmt error: %s`, err)
+	}
+	fields := strings.Fields(line.String())
+	if len(fields) != 12 || fields[0] != "BenchmarkChunker/algorithm=fastcdc/corpus=twice" || fields[1] != "1" {
+		t.Fatalf(`malformed benchfmt line %q`, line.String())
+	}
+	for i := 3; i < len(fields); i += 2 {
+		if _, err := strconv.ParseFloat(fields[i-1], 64); err != nil {
+			t.Fatalf(`malformed value %q for unit %s`, fields[i-1], fields[i])
+		}
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf(`json error: %s`, err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf(`json error: %s`, err)
+	}
+	for _, key := range []string{"algorithm", "corpus", "gbps", "chunks_per_sec", "allocs_per_pass", "dedup_ratio"} {
+		if _, exists := decoded[key]; !exists {
+			t.Fatalf(`json output is missing %s`, key)
+		}
+	}
+}
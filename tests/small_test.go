@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+// block-level deduplication runs far below the usual averages
+var smallAverages = []int{1 << 10, 2 << 10, 4 << 10}
+
+var smallAlgorithms = []string{"fastcdc@v2", "fastcdc2020@v2", "jc@v2", "ultracdc@v2", "gear", "bupsplit", "mii", "restic", "casync", "quickcdc", "rapidcdc", "sourcecode", "seqcdc", "pci", "maxp", "fixed", "winnowing", "rsync", "lbfs", "zstd-rsyncable"}
+
+func smallOpts(average int) *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{MinSize: average / 4, NormalSize: average, MaxSize: 4 * average}
+}
+
+func Test_SmallChunks(t *testing.T) {
+	data := rb[:8<<20]
+
+	for _, average := range smallAverages {
+		for _, algorithm := range smallAlgorithms {
+			opts := smallOpts(average)
+			chunker, err := chunkers.NewChunker(algorithm, bytes.NewReader(data), opts)
+			if err != nil {
+				t.Fatalf(`%s: chunker error: %s`, algorithm, err)
+			}
+
+			chunks, forced := 0, 0
+			for {
+				chunk, err := chunker.Next()
+				if err != nil && err != io.EOF {
+					t.Fatalf(`%s: chunker error: %s`, algorithm, err)
+				}
+				if len(chunk) > opts.MaxSize {
+					t.Fatalf(`%s: chunk of %d bytes above MaxSize`, algorithm, len(chunk))
+				}
+				if len(chunk) != 0 {
+					chunks++
+				}
+				if chunker.Flags()&chunkers.FlagForced != 0 {
+					forced++
+				}
+				if err == io.EOF {
+					break
+				}
+			}
+
+			// masks tuned for larger chunks cut most small ones at MaxSize
+			if ratio := float64(forced) / float64(chunks); ratio > 0.03 {
+				t.Fatalf(`%s: %.1f%% of %dB chunks forced`, algorithm, 100*ratio, average)
+			}
+		}
+	}
+
+	// algorithms aiming at NormalSize keep doing so
+	for _, average := range smallAverages {
+		for _, algorithm := range []string{"fastcdc@v2", "fastcdc2020@v2", "jc@v2", "ultracdc@v2", "seqcdc", "casync", "quickcdc", "rapidcdc", "restic", "lbfs"} {
+			if m := mean(splitLengths(t, algorithm, data, smallOpts(average))); m < 0.5*float64(average) || m > 1.5*float64(average) {
+				t.Fatalf(`%s: average chunk size of %.0fB for a NormalSize of %dB`, algorithm, m, average)
+			}
+		}
+	}
+}
+
+func Benchmark_SmallChunks(b *testing.B) {
+	data := rb[:64<<20]
+
+	for _, average := range smallAverages {
+		for _, algorithm := range smallAlgorithms {
+			b.Run(fmt.Sprintf("%s/%dKiB", algorithm, average>>10), func(b *testing.B) {
+				opts := smallOpts(average)
+				opts.BorrowBuffers = true
+				r := bytes.NewReader(data)
+				b.SetBytes(int64(len(data)))
+				b.ResetTimer()
+				nchunks := 0
+				for i := 0; i < b.N; i++ {
+					chunker, err := chunkers.NewChunker(algorithm, r, opts)
+					if err != nil {
+						b.Fatalf(`chunker error: %s`, err)
+					}
+					err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+						nchunks++
+						return nil
+					})
+					if err != nil {
+						b.Fatalf(`chunker error: %s`, err)
+					}
+					r.Reset(data)
+				}
+				b.ReportMetric(float64(nchunks)/float64(b.N), "chunks")
+			})
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	_ "github.com/PlakarKorp/go-cdc-chunkers/chunkers/sourcecode"
+)
+
+// sourceCorpus concatenates Go sources from GOROOT, the closest thing to
+// a monorepo available everywhere the tests run, along with a copy where
+// a line is inserted in the middle of every tenth file.
+func sourceCorpus(t *testing.T, size int) ([]byte, []byte) {
+	root := filepath.Join(runtime.GOROOT(), "src")
+	var original, mutated []byte
+	files := 0
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || len(original) >= size {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		original = append(original, data...)
+		if files++; files%10 == 0 {
+			if i := bytes.IndexByte(data[len(data)/2:], '\n'); i >= 0 {
+				i += len(data)/2 + 1
+				data = append(data[:i:i], append([]byte("\t// edited\n"), data[i:]...)...)
+			}
+		}
+		mutated = append(mutated, data...)
+		return nil
+	})
+	if err != nil || len(original) < size {
+		t.Skipf(`GOROOT sources unavailable: %v`, err)
+	}
+	return original, mutated
+}
+
+func Test_SourceCode(t *testing.T) {
+	original, mutated := sourceCorpus(t, 16<<20)
+
+	comparison, err := chunkers.Compare("sourcecode", "fastcdc", nil, original, mutated)
+	if err != nil {
+		t.Fatalf(`compare error: %s`, err)
+	}
+	sourcecode, fastcdc := comparison.A, comparison.B
+	if sourcecode.DedupRatio < fastcdc.DedupRatio {
+		t.Fatalf(`sourcecode dedups worse than fastcdc defaults: %f < %f`, sourcecode.DedupRatio, fastcdc.DedupRatio)
+	}
+	if sourcecode.AverageSize < 6<<10 || sourcecode.AverageSize > 10<<10 {
+		t.Fatalf(`average chunk size %f too far from 8KB`, sourcecode.AverageSize)
+	}
+
+	// chunks hold whole lines
+	chunker, err := chunkers.NewChunker("sourcecode", bytes.NewReader(original), nil)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	chunks, lines := 0, 0
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		chunks++
+		if chunk[len(chunk)-1] == '\n' {
+			lines++
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if lines < chunks*99/100 {
+		t.Fatalf(`only %d of %d chunks end with a line`, lines, chunks)
+	}
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	tarhints "github.com/PlakarKorp/go-cdc-chunkers/hints/tar"
+)
+
+func Test_ResumeChunker(t *testing.T) {
+	// stateful algorithms jump over the repeated half
+	data := slices.Concat(rb[:2<<20], rb[:2<<20])
+
+	for _, algorithm := range []string{"fastcdc", "quickcdc", "rapidcdc"} {
+		opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, StreamIdentity: true}
+		chunker, err := chunkers.NewChunker(algorithm, bytes.NewReader(data), opts)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		expected := cuts(t, chunker)
+		identity := chunker.StreamIdentity()
+
+		// interrupted halfway through the first half
+		chunker, err = chunkers.NewChunker(algorithm, bytes.NewReader(data), opts)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		var offsets []uint64
+		offset := uint64(0)
+		for uint64(len(offsets)) < uint64(len(expected)/4) {
+			chunk, err := chunker.Next()
+			if err != nil {
+				t.Fatalf(`chunker error: %s`, err)
+			}
+			offsets = append(offsets, offset)
+			offset += uint64(len(chunk))
+		}
+		state, err := chunker.State()
+		if err != nil {
+			t.Fatalf(`%s: state error: %s`, algorithm, err)
+		}
+
+		resumeOffset, err := chunkers.ResumeOffset(state)
+		if err != nil {
+			t.Fatalf(`%s: state error: %s`, algorithm, err)
+		}
+		if resumeOffset < int64(offset) || resumeOffset > int64(len(data)) {
+			t.Fatalf(`%s: resuming at %d after chunks up to %d`, algorithm, resumeOffset, offset)
+		}
+		resumed, err := chunkers.ResumeChunker(algorithm, bytes.NewReader(data[resumeOffset:]), state, opts)
+		if err != nil {
+			t.Fatalf(`%s: resume error: %s`, algorithm, err)
+		}
+		offsets = append(offsets, cuts(t, resumed)...)
+		if !slices.Equal(offsets, expected) {
+			t.Fatalf(`%s: resumed chunker cut %d chunks, %d expected`, algorithm, len(offsets), len(expected))
+		}
+		if !bytes.Equal(resumed.StreamIdentity(), identity) {
+			t.Fatalf(`%s: resumed chunker has another identity`, algorithm)
+		}
+
+		// the state only resumes the same algorithm and options
+		other := *opts
+		other.MinSize = 4 << 10
+		if _, err := chunkers.ResumeChunker(algorithm, bytes.NewReader(nil), state, &other); err != chunkers.ErrStateOptions {
+			t.Fatalf(`%s: resumed with other options: %v`, algorithm, err)
+		}
+		if _, err := chunkers.ResumeChunker(algorithm, bytes.NewReader(nil), state[:len(state)-1], opts); err != chunkers.ErrState {
+			t.Fatalf(`%s: resumed from a truncated state: %v`, algorithm, err)
+		}
+	}
+	if _, err := chunkers.ResumeChunker("fastcdc", bytes.NewReader(nil), []byte("state"), nil); err != chunkers.ErrState {
+		t.Fatalf(`resumed from an invalid state: %v`, err)
+	}
+}
+
+func Test_State_Unresumable(t *testing.T) {
+	opts := &chunkers.ChunkerOpts{MinSize: 100, NormalSize: 1000, MaxSize: 2000}
+	scanned := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, Scanner: tarhints.NewScanner}
+	for algorithm, opts := range map[string]*chunkers.ChunkerOpts{"test-stateful": opts, "fastcdc": scanned} {
+		chunker, err := chunkers.NewChunker(algorithm, bytes.NewReader(rb[:1<<20]), opts)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		if _, err := chunker.Next(); err != nil && err != io.EOF {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		if _, err := chunker.State(); err != chunkers.ErrUnresumable {
+			t.Fatalf(`%s: got %v, expected %v`, algorithm, err, chunkers.ErrUnresumable)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+// recorder cuts every NormalSize bytes and records what it is told
+type recorder struct {
+	emitted [][]byte
+}
+
+func (r *recorder) DefaultOptions() *chunkers.ChunkerOpts {
+	return &chunkers.ChunkerOpts{MinSize: 100, NormalSize: 1000, MaxSize: 2000}
+}
+
+func (r *recorder) Validate(*chunkers.ChunkerOpts) error {
+	return nil
+}
+
+func (r *recorder) Algorithm(options *chunkers.ChunkerOpts, data []byte, n int) int {
+	return min(n, options.NormalSize)
+}
+
+func (r *recorder) Emit(options *chunkers.ChunkerOpts, chunk []byte) {
+	r.emitted = append(r.emitted, append([]byte(nil), chunk...))
+}
+
+var lastRecorder *recorder
+
+func init() {
+	chunkers.Register("test-stateful", func() chunkers.ChunkerImplementation {
+		lastRecorder = &recorder{}
+		return lastRecorder
+	})
+}
+
+func Test_StatefulEmit(t *testing.T) {
+	data := rb[:10500]
+
+	// the 500 bytes tail is merged into the last chunk before Emit
+	opts := &chunkers.ChunkerOpts{MinSize: 100, NormalSize: 1000, MaxSize: 2000, MinTailSize: 600}
+	chunker, err := chunkers.NewChunker("test-stateful", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	var chunks [][]byte
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	emitted := lastRecorder.emitted
+	if len(emitted) != len(chunks) || len(chunks) != 10 {
+		t.Fatalf(`expected 10 chunks emitted, got %d emitted for %d chunks`, len(emitted), len(chunks))
+	}
+	for i := range chunks {
+		if !bytes.Equal(emitted[i], chunks[i]) {
+			t.Fatalf(`chunk %d: emitted data differs from the chunk handed out`, i)
+		}
+	}
+	if len(emitted[9]) != 1500 {
+		t.Fatalf(`expected the tail to be merged before Emit, got a last chunk of %d bytes`, len(emitted[9]))
+	}
+}
@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"testing/iotest"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_Stream(t *testing.T) {
+	data := rb[:4<<20]
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	var offsets, lengths []uint64
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		offsets = append(offsets, offset)
+		lengths = append(lengths, length)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	// a negative depth is unbuffered as zero
+	for _, depth := range []int{-1, 0, 16} {
+		borrowed := *opts
+		borrowed.BorrowBuffers = true
+		chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), &borrowed)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		// chunks remain valid once received, even borrowed
+		var chunks []chunkers.Chunk
+		for item := range chunker.Stream(context.Background(), depth) {
+			if item.Err != nil {
+				t.Fatalf(`chunker error: %s`, item.Err)
+			}
+			chunks = append(chunks, item.Chunk)
+		}
+		if len(chunks) != len(offsets) {
+			t.Fatalf(`depth %d: %d chunks streamed, %d expected`, depth, len(chunks), len(offsets))
+		}
+		for i, chunk := range chunks {
+			if uint64(chunk.Offset) != offsets[i] || uint64(chunk.Length) != lengths[i] ||
+				!bytes.Equal(chunk.Data, data[offsets[i]:offsets[i]+lengths[i]]) {
+				t.Fatalf(`depth %d: chunk %d differs`, depth, i)
+			}
+		}
+	}
+}
+
+func Test_Stream_Stop(t *testing.T) {
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+
+	// the error stopping the stream is received last
+	chunker, err := chunkers.NewChunker("fastcdc", iotest.TimeoutReader(iotest.HalfReader(bytes.NewReader(rb[:1<<20]))), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	var last error
+	for item := range chunker.Stream(context.Background(), 4) {
+		last = item.Err
+	}
+	if !errors.Is(last, iotest.ErrTimeout) {
+		t.Fatalf(`got %v, expected %v`, last, iotest.ErrTimeout)
+	}
+
+	// a canceled stream is closed without being consumed
+	ctx, cancel := context.WithCancel(context.Background())
+	chunker, err = chunkers.NewChunker("fastcdc", bytes.NewReader(rb[:16<<20]), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	ch := chunker.Stream(ctx, 4)
+	<-ch
+	cancel()
+	received := 0
+	for range ch {
+		received++
+	}
+	if received > 5 {
+		t.Fatalf(`%d chunks received after cancellation`, received)
+	}
+}
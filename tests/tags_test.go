@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_Tags(t *testing.T) {
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	data := bytes.Clone(rb[:4<<20])
+	marker := []byte("4111-1111-1111-1111")
+	copy(data[1<<20:], marker)
+	lengths := splitLengths(t, "fastcdc", data, opts)
+
+	tagged := *opts
+	tagged.Tagger = func(offset, length uint64, chunk []byte) map[string]string {
+		tags := map[string]string{"tier": "cold"}
+		if bytes.Contains(chunk, marker) {
+			tags["pii"] = "card"
+		}
+		return tags
+	}
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), &tagged)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	var tagLengths []int
+	pii := 0
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		tagLengths = append(tagLengths, int(length))
+		tags := chunker.Tags()
+		if tags["tier"] != "cold" {
+			t.Fatalf(`chunk at %d not tagged: %v`, offset, tags)
+		}
+		if _, found := tags["pii"]; found {
+			pii++
+			if offset > 1<<20 || offset+length < 1<<20+uint64(len(marker)) {
+				t.Fatalf(`chunk at %d tagged pii`, offset)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if pii != 1 {
+		t.Fatalf(`%d chunks tagged pii`, pii)
+	}
+
+	// tags do not move cutpoints
+	if !slices.Equal(tagLengths, lengths) {
+		t.Fatalf(`tagging changed the cutpoints`)
+	}
+}
+
+func Test_TagsOffsets(t *testing.T) {
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	data := rb[:1<<20]
+	lengths := splitLengths(t, "fastcdc", data, opts)
+	anchor := int64(lengths[0] + lengths[1])
+
+	// the tagger sees the offsets Split reports, anchors included
+	var offsets []uint64
+	tagged := *opts
+	tagged.Tagger = func(offset, length uint64, chunk []byte) map[string]string {
+		if int(length) != len(chunk) {
+			t.Fatalf(`length %d for a chunk of %d bytes`, length, len(chunk))
+		}
+		offsets = append(offsets, offset)
+		return nil
+	}
+	chunker, err := chunkers.NewChunkerAt("fastcdc", bytes.NewReader(data), anchor, &tagged)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	var expected []uint64
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		expected = append(expected, offset)
+		if chunker.Tags() != nil {
+			t.Fatalf(`unexpected tags %v`, chunker.Tags())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if expected[0] != uint64(anchor) || !slices.Equal(offsets, expected) {
+		t.Fatalf(`tagger offsets %v, Split offsets %v`, offsets[:3], expected[:3])
+	}
+}
@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func splitLengths(t *testing.T, algorithm string, data []byte, opts *chunkers.ChunkerOpts) []int {
+	chunker, err := chunkers.NewChunker(algorithm, bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	var lengths []int
+	for {
+		chunk, err := chunker.Next()
+		if err != nil && err != io.EOF {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		if len(chunk) != 0 {
+			lengths = append(lengths, len(chunk))
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	return lengths
+}
+
+func Test_MinTailSize(t *testing.T) {
+	for _, algorithm := range []string{"fastcdc", "jc", "ultracdc"} {
+		opts := &chunkers.ChunkerOpts{
+			MinSize:    2 << 10,
+			NormalSize: 8 << 10,
+			MaxSize:    64 << 10,
+		}
+
+		lengths := splitLengths(t, algorithm, rb[:1<<20], opts)
+		cut := 0
+		for _, length := range lengths[:len(lengths)-2] {
+			cut += length
+		}
+		data := rb[:cut+100]
+
+		lengths = splitLengths(t, algorithm, data, opts)
+		if lengths[len(lengths)-1] != 100 {
+			t.Fatalf(`%s: expected a 100 bytes tail, got %d`, algorithm, lengths[len(lengths)-1])
+		}
+
+		opts.MinTailSize = 1024
+		merged := splitLengths(t, algorithm, data, opts)
+		if len(merged) != len(lengths)-1 {
+			t.Fatalf(`%s: expected %d chunks, got %d`, algorithm, len(lengths)-1, len(merged))
+		}
+		if merged[len(merged)-1] != lengths[len(lengths)-2]+100 {
+			t.Fatalf(`%s: tail was not merged into the previous chunk`, algorithm)
+		}
+	}
+}
+
+// A merged tail is cut by EOF, not by the algorithm, and carries no flags.
+func Test_MinTailSize_Flags(t *testing.T) {
+	opts := &chunkers.ChunkerOpts{
+		MinSize:    2 << 10,
+		NormalSize: 8 << 10,
+		MaxSize:    64 << 10,
+	}
+	lengths := splitLengths(t, "ultracdc", make([]byte, 1<<20), opts)
+	cut := 0
+	for _, length := range lengths[:len(lengths)-2] {
+		cut += length
+	}
+	data := make([]byte, cut+100)
+
+	opts.MinTailSize = 1024
+	chunker, err := chunkers.NewChunker("ultracdc", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		expected := chunkers.FlagLowEntropy
+		if offset+length == uint64(len(data)) {
+			expected = 0
+		}
+		if chunker.Flags() != expected {
+			t.Fatalf(`chunk at offset %d flagged %d, expected %d`, offset, chunker.Flags(), expected)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if stats := chunker.CutStats(); stats.LowEntropy != stats.Chunks-1 {
+		t.Fatalf(`unexpected stats %+v`, stats)
+	}
+}
@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"math/rand"
+	"slices"
+	"strconv"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	tarhints "github.com/PlakarKorp/go-cdc-chunkers/hints/tar"
+)
+
+// tarball archives the files in order, returning the archive and the
+// offsets of the contents of the files.
+func tarball(t *testing.T, files [][]byte, order []int) ([]byte, []int64) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	var contents []int64
+	for _, i := range order {
+		hdr := &tar.Header{Name: "file" + strconv.Itoa(i), Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(files[i]))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		contents = append(contents, int64(buf.Len()))
+		if _, err := tw.Write(files[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes(), contents
+}
+
+// reused returns the bytes of b in chunks also found in a.
+func reused(t *testing.T, a, b []byte, opts *chunkers.ChunkerOpts) int {
+	digests := make(map[[32]byte]bool)
+	total := 0
+	for i, data := range [][]byte{a, b} {
+		chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+			digest := sha256.Sum256(chunk)
+			if i == 0 {
+				digests[digest] = true
+			} else if digests[digest] {
+				total += len(chunk)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+	}
+	return total
+}
+
+func Test_TarScanner(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	files := make([][]byte, 200)
+	offset := 0
+	for i := range files {
+		size := 4<<10 + rng.Intn(60<<10)
+		files[i] = rb[offset : offset+size]
+		offset += size
+	}
+	order := rng.Perm(len(files))
+	original, _ := tarball(t, files, order)
+	rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	shuffled, contents := tarball(t, files, order)
+
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	scanned := *opts
+	scanned.Scanner = tarhints.NewScanner
+	scanned.HintTolerance = 8 << 10
+
+	// cutpoints snap to the start of the contents of files
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(shuffled), &scanned)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	snapped := 0
+	err = chunker.Split(func(offset, length uint64, chunk []byte) error {
+		if _, found := slices.BinarySearch(contents, int64(offset+length)); found {
+			snapped++
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if snapped < len(contents)*9/10 {
+		t.Fatalf(`only %d out of %d files start a chunk`, snapped, len(contents))
+	}
+
+	// which dedups the files of a reordered archive better, the chunks
+	// at the end of files still holding the header of the next one
+	plain, hinted := reused(t, original, shuffled, opts), reused(t, original, shuffled, &scanned)
+	if hinted-plain < len(shuffled)/20 {
+		t.Fatalf(`scanner reused %d bytes, %d without`, hinted, plain)
+	}
+}
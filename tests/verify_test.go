@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"io"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_CopyVerify(t *testing.T) {
+	data := rb[:4<<20]
+	digest := sha256.Sum256(data)
+
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	var copied bytes.Buffer
+	if _, err := chunker.CopyVerify(&copied, digest[:]); err != io.EOF {
+		t.Fatalf(`expected io.EOF, got %v`, err)
+	}
+	if !bytes.Equal(copied.Bytes(), data) {
+		t.Fatalf(`copy does not match the input`)
+	}
+
+	// a single flipped byte must be caught
+	tampered := bytes.Clone(data)
+	tampered[len(tampered)/2] ^= 1
+	chunker, err = chunkers.NewChunker("fastcdc", bytes.NewReader(tampered), nil)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	_, err = chunker.CopyVerify(io.Discard, digest[:])
+	var mismatch *chunkers.DigestMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf(`expected a DigestMismatchError, got %v`, err)
+	}
+	if actual := sha256.Sum256(tampered); !bytes.Equal(mismatch.Actual, actual[:]) || !bytes.Equal(mismatch.Expected, digest[:]) {
+		t.Fatalf(`unexpected digests in %v`, mismatch)
+	}
+}
+
+func Test_CopyVerify_Hash(t *testing.T) {
+	data := rb[:1<<20]
+	hasher := chunkers.NewXXH64()
+	hasher.Write(data)
+
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, Hash: func() hash.Hash { return chunkers.NewXXH64() }}
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if _, err := chunker.CopyVerify(io.Discard, hasher.Sum(nil)); err != io.EOF {
+		t.Fatalf(`expected io.EOF, got %v`, err)
+	}
+}
+
+// Verifying keeps handing chunks to OffsetWriter implementations at their
+// offset, as Copy does.
+func Test_CopyVerify_OffsetWriter(t *testing.T) {
+	data := rb[:4<<20]
+	digest := sha256.Sum256(data)
+
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	w := &offsetWriter{}
+	if _, err := chunker.CopyVerify(w, digest[:]); err != io.EOF {
+		t.Fatalf(`expected io.EOF, got %v`, err)
+	}
+	offset := uint64(0)
+	for i := range w.offsets {
+		if w.offsets[i] != offset {
+			t.Fatalf(`chunk %d written at offset %d, expected %d`, i, w.offsets[i], offset)
+		}
+		offset += w.lengths[i]
+	}
+	if offset != uint64(len(data)) {
+		t.Fatalf(`%d bytes written, expected %d`, offset, len(data))
+	}
+}
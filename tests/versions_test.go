@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/gear"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/ultracdc"
+)
+
+func init() {
+	// a bare name registers v1, which it keeps resolving to once v2 is
+	// registered
+	chunkers.Register("test-versioned", func() chunkers.ChunkerImplementation { return &gear.Gear{} })
+	chunkers.Register("test-versioned@v2", func() chunkers.ChunkerImplementation { return &ultracdc.UltraCDC{} })
+}
+
+func Test_Versions(t *testing.T) {
+	data := rb[:1<<20]
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	v1 := splitLengths(t, "gear", data, opts)
+	v2 := splitLengths(t, "ultracdc", data, opts)
+
+	for name, expected := range map[string]string{"fastcdc": "fastcdc@v1", "fastcdc@v1": "fastcdc@v1", "test-versioned": "test-versioned@v1", "test-versioned@v2": "test-versioned@v2"} {
+		if resolved, err := chunkers.Resolve(name); err != nil || resolved != expected {
+			t.Fatalf(`%s resolved to %q, expected %q: %v`, name, resolved, expected, err)
+		}
+	}
+	for name, expected := range map[string][]int{"test-versioned": v1, "test-versioned@v1": v1, "test-versioned@v2": v2} {
+		if !slices.Equal(splitLengths(t, name, data, opts), expected) {
+			t.Fatalf(`%s cut at other cutpoints`, name)
+		}
+	}
+
+	// the bare name can be pinned to another version
+	if err := chunkers.SetDefaultVersion("test-versioned", "v2"); err != nil {
+		t.Fatalf(`version error: %s`, err)
+	}
+	defer chunkers.SetDefaultVersion("test-versioned", "v1")
+	chunker, err := chunkers.NewChunker("test-versioned", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	if chunker.AlgorithmName() != "test-versioned@v2" {
+		t.Fatalf(`algorithm %q, expected test-versioned@v2`, chunker.AlgorithmName())
+	}
+	if !slices.Equal(splitLengths(t, "test-versioned", data, opts), v2) {
+		t.Fatalf(`test-versioned not pinned to v2`)
+	}
+	if err := chunkers.SetDefaultVersion("test-versioned", "v3"); err == nil {
+		t.Fatalf(`unknown version pinned`)
+	}
+
+	for _, name := range []string{"test-versioned@v1", "test-versioned", "@v1", "test-versioned@", "test-versioned@v1@v2"} {
+		if err := chunkers.Register(name, func() chunkers.ChunkerImplementation { return &gear.Gear{} }); err == nil {
+			t.Fatalf(`%s registered`, name)
+		}
+	}
+	if _, err := chunkers.Resolve("test-versioned@v3"); err == nil {
+		t.Fatalf(`unknown version resolved`)
+	}
+}
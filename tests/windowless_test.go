@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"slices"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+	"github.com/PlakarKorp/go-cdc-chunkers/chunkers/fastcdc"
+)
+
+// windowlessGear is Gear rebuilt from its inner step
+type windowlessGear struct {
+	chunkers.Windowless
+}
+
+func newWindowlessGear() chunkers.ChunkerImplementation {
+	return &windowlessGear{chunkers.Windowless{
+		Roll: func(fingerprint uint64, b byte) uint64 {
+			return fingerprint<<1 + fastcdc.G[b]
+		},
+	}}
+}
+
+func init() {
+	chunkers.Register("windowless-gear", newWindowlessGear)
+}
+
+func Test_Windowless(t *testing.T) {
+	data := rb[:16<<20]
+
+	// without normalization, the scaffolding is exactly gear
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10, NormalizationLevel: -1}
+	if err := chunkers.CheckCutpoints(newWindowlessGear(), opts, data); err != nil {
+		t.Fatal(err)
+	}
+	expected := splitLengths(t, "gear", data, opts)
+	lengths := splitLengths(t, "windowless-gear", data, opts)
+	if len(lengths) != len(expected) {
+		t.Fatalf(`expected %d chunks, got %d`, len(expected), len(lengths))
+	}
+	for i := range lengths {
+		if lengths[i] != expected[i] {
+			t.Fatalf(`chunk %d: expected %d bytes, got %d`, i, expected[i], lengths[i])
+		}
+	}
+
+	// gear runs the scaffolding with normalization off whatever the level
+	for _, level := range []int{0, 3} {
+		opts.NormalizationLevel = level
+		unnormalized := splitLengths(t, "gear", data, opts)
+		if !slices.Equal(unnormalized, expected) {
+			t.Fatalf(`gear normalized at level %d`, level)
+		}
+	}
+
+	// normalization narrows the distribution around NormalSize
+	opts.NormalizationLevel = 0
+	if err := chunkers.CheckCutpoints(newWindowlessGear(), opts, data); err != nil {
+		t.Fatal(err)
+	}
+	normalized := splitLengths(t, "windowless-gear", data, opts)
+	if stddev(normalized) >= stddev(lengths) {
+		t.Fatalf(`normalization did not narrow the chunk size distribution`)
+	}
+
+	opts.NormalizationLevel = 4
+	if err := newWindowlessGear().Validate(opts); err != chunkers.ErrWindowlessNormalization {
+		t.Fatalf(`expected ErrWindowlessNormalization, got %v`, err)
+	}
+}
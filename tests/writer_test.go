@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	chunkers "github.com/PlakarKorp/go-cdc-chunkers"
+)
+
+func Test_NewWriter(t *testing.T) {
+	data := rb[:4<<20]
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+
+	chunker, err := chunkers.NewChunker("fastcdc", bytes.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+	var expected []chunkers.Chunk
+	for {
+		chunk, err := chunker.NextChunk()
+		if len(chunk.Data) != 0 {
+			expected = append(expected, chunk)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+	}
+
+	// whatever the size of writes, the same chunks are emitted
+	for _, size := range []int{7, 1000, 64 << 10, 1 << 20, len(data)} {
+		var chunks []chunkers.Chunk
+		w, err := chunkers.NewWriter("fastcdc", opts, func(chunk chunkers.Chunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf(`chunker error: %s`, err)
+		}
+		for offset := 0; offset < len(data); offset += size {
+			// chunks are only emitted within Write, up to MaxSize bytes
+			// behind
+			n, err := w.Write(data[offset:min(offset+size, len(data))])
+			if err != nil {
+				t.Fatalf(`write error: %s`, err)
+			}
+			emitted := 0
+			if len(chunks) != 0 {
+				last := chunks[len(chunks)-1]
+				emitted = int(last.Offset) + len(last.Data)
+			}
+			if offset+n-emitted > opts.MaxSize+size {
+				t.Fatalf(`writes of %d bytes: %d bytes written, %d emitted`, size, offset+n, emitted)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf(`close error: %s`, err)
+		}
+		if len(chunks) != len(expected) {
+			t.Fatalf(`writes of %d bytes: %d chunks, %d expected`, size, len(chunks), len(expected))
+		}
+		for i := range chunks {
+			if chunks[i].Offset != expected[i].Offset || !bytes.Equal(chunks[i].Data, expected[i].Data) || !bytes.Equal(chunks[i].Digest, expected[i].Digest) {
+				t.Fatalf(`writes of %d bytes: chunk %d differs`, size, i)
+			}
+		}
+		if _, err := w.Write(data); err != io.ErrClosedPipe {
+			t.Fatalf(`write after close: got %v, expected %v`, err, io.ErrClosedPipe)
+		}
+	}
+
+	if _, err := chunkers.NewWriter("unknown", nil, nil); err == nil {
+		t.Fatalf(`unknown algorithm accepted`)
+	}
+}
+
+func Test_NewWriter_EmitError(t *testing.T) {
+	opts := &chunkers.ChunkerOpts{MinSize: 2 << 10, NormalSize: 8 << 10, MaxSize: 64 << 10}
+	failure := errors.New("emit failed")
+	var offsets []uint64
+	w, err := chunkers.NewWriter("fastcdc", opts, func(chunk chunkers.Chunk) error {
+		if len(offsets) == 3 {
+			return failure
+		}
+		offsets = append(offsets, chunk.Offset)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`chunker error: %s`, err)
+	}
+
+	var werr error
+	for offset := 0; offset < 1<<20 && werr == nil; offset += 4 << 10 {
+		_, werr = w.Write(rb[offset : offset+4<<10])
+	}
+	if werr != failure {
+		t.Fatalf(`write: got %v, expected %v`, werr, failure)
+	}
+	if _, err := w.Write(rb[:1]); err != failure {
+		t.Fatalf(`write after failure: got %v, expected %v`, err, failure)
+	}
+	if err := w.Close(); err != failure {
+		t.Fatalf(`close: got %v, expected %v`, err, failure)
+	}
+	if !slices.IsSorted(offsets) || len(offsets) != 3 {
+		t.Fatalf(`emitted %v`, offsets)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package chunkers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// DigestMismatchError is returned by CopyVerify when the stream does not
+// hash to the expected digest.
+type DigestMismatchError struct {
+	Expected []byte
+	Actual   []byte
+}
+
+func (e *DigestMismatchError) Error() string {
+	return fmt.Sprintf("stream digest %x does not match the expected %x", e.Actual, e.Expected)
+}
+
+// CopyVerify behaves like Copy while hashing the whole stream with
+// ChunkerOpts.Hash, so that an upload is transferred and verified in a
+// single pass. Once the stream is exhausted, it fails with a
+// *DigestMismatchError if the digest differs from expected, in which
+// case everything was already written to dst and must be discarded.
+func (chunker *Chunker) CopyVerify(dst io.Writer, expected []byte) (int64, error) {
+	newHash := chunker.options.Hash
+	if newHash == nil {
+		newHash = sha256.New
+	}
+	hasher := newHash()
+
+	nbytes, err := chunker.Copy(&hashingWriter{dst: dst, hasher: hasher})
+	if err != io.EOF {
+		return nbytes, err
+	}
+	if actual := hasher.Sum(nil); !bytes.Equal(actual, expected) {
+		return nbytes, &DigestMismatchError{Expected: expected, Actual: actual}
+	}
+	return nbytes, io.EOF
+}
+
+// hashingWriter hashes the chunks it writes to dst, like io.MultiWriter
+// but keeping the OffsetWriter path of dst.
+type hashingWriter struct {
+	dst    io.Writer
+	hasher hash.Hash
+}
+
+func (w *hashingWriter) Write(p []byte) (int, error) {
+	n, err := w.dst.Write(p)
+	return w.hashed(p, n, err)
+}
+
+func (w *hashingWriter) WriteChunk(offset uint64, p []byte) (int, error) {
+	n, err := write(w.dst, offset, p)
+	return w.hashed(p, n, err)
+}
+
+// hashed hashes p once it was written whole to dst.
+func (w *hashingWriter) hashed(p []byte, n int, err error) (int, error) {
+	if err == nil && n != len(p) {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		return n, err
+	}
+	w.hasher.Write(p)
+	return n, nil
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+package chunkers
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+var ErrWindowlessSizes = errors.New("sizes must be 64B <= MinSize < NormalSize < MaxSize <= 1GB")
+var ErrWindowlessNormalization = errors.New("NormalizationLevel must be -1 <= NormalizationLevel <= 3 and leave a loose mask of at least one bit")
+
+// DefaultWindowlessNormalizationLevel is the NormalizationLevel used by
+// Windowless when it is zero.
+const DefaultWindowlessNormalizationLevel = 2
+
+// WindowlessOptions tunes the algorithms built on Windowless when passed
+// as ChunkerOpts.Extension.
+type WindowlessOptions struct {
+	// NormalizationLevel sets how far the mask before NormalSize widens
+	// and the one past it narrows, from 1 to 3. -1 disables normalization
+	// and zero selects DefaultWindowlessNormalizationLevel.
+	NormalizationLevel int
+}
+
+// WithWindowlessNormalization sets the NormalizationLevel of the
+// Extension.
+func WithWindowlessNormalization(level int) Option {
+	return func(opts *ChunkerOpts) {
+		ext := &WindowlessOptions{}
+		if current, ok := opts.Extension.(*WindowlessOptions); ok {
+			*ext = *current
+		}
+		ext.NormalizationLevel = level
+		opts.Extension = ext
+	}
+}
+
+// Windowless implements ChunkerImplementation for windowless rolling-hash
+// algorithms, such as Gear, from their inner step alone. It skips the
+// first MinSize bytes, judges fingerprints with a strict mask before
+// NormalSize and a loose one past it, clamps chunks at MaxSize and
+// validates the options, so that an algorithm only provides Roll:
+//
+//	type MyCDC struct {
+//		chunkers.Windowless
+//	}
+//
+//	func newMyCDC() chunkers.ChunkerImplementation {
+//		return &MyCDC{chunkers.Windowless{Roll: roll}}
+//	}
+//
+// Masks select the most significant bits of the fingerprint, which depend
+// on the most bytes with shift-based rolls. Their width is the largest
+// power of two not above NormalSize - MinSize, widened before NormalSize
+// and narrowed past it by the normalization level.
+type Windowless struct {
+	// Roll returns the fingerprint once b was rolled in, fingerprints
+	// starting from zero at MinSize.
+	Roll func(fingerprint uint64, b byte) uint64
+
+	// Judge reports whether a fingerprint is a cutpoint under mask, nil
+	// tests that the masked bits are all zero.
+	Judge func(fingerprint, mask uint64) bool
+
+	// Unnormalized judges every fingerprint with the same mask, whatever
+	// the normalization level, as Gear does.
+	Unnormalized bool
+}
+
+func (w *Windowless) DefaultOptions() *ChunkerOpts {
+	return &ChunkerOpts{
+		MinSize:    2 * 1024,
+		MaxSize:    64 * 1024,
+		NormalSize: 8 * 1024,
+	}
+}
+
+func (w *Windowless) Validate(options *ChunkerOpts) error {
+	if options.MinSize < 64 || options.MinSize >= options.NormalSize ||
+		options.NormalSize >= options.MaxSize || options.MaxSize > 1024*1024*1024 {
+		return ErrWindowlessSizes
+	}
+	if level := windowlessLevel(options); level < -1 || level > 3 {
+		return ErrWindowlessNormalization
+	}
+	if _, loose := w.maskBits(options); loose < 1 {
+		return ErrWindowlessNormalization
+	}
+	return nil
+}
+
+// windowlessLevel returns the normalization level set for options, the
+// one of the Extension taking precedence.
+func windowlessLevel(options *ChunkerOpts) int {
+	if ext, ok := options.Extension.(*WindowlessOptions); ok && ext.NormalizationLevel != 0 {
+		return ext.NormalizationLevel
+	}
+	return options.NormalizationLevel
+}
+
+// maskBits returns the width of the masks in effect before and past
+// NormalSize.
+func (w *Windowless) maskBits(options *ChunkerOpts) (int, int) {
+	level := windowlessLevel(options)
+	switch {
+	case w.Unnormalized:
+		level = 0
+	case level == 0:
+		level = DefaultWindowlessNormalizationLevel
+	case level == -1:
+		level = 0
+	}
+	width := bits.Len(uint(options.NormalSize-options.MinSize)) - 1
+	return width + level, width - level
+}
+
+func (w *Windowless) Algorithm(options *ChunkerOpts, data []byte, n int) int {
+	MinSize := options.MinSize
+	MaxSize := options.MaxSize
+	NormalSize := options.NormalSize
+
+	switch {
+	case n <= MinSize:
+		return n
+	case n >= MaxSize:
+		n = MaxSize
+	case n <= NormalSize:
+		NormalSize = n
+	}
+
+	strict, loose := w.maskBits(options)
+	maskS := ^uint64(0) << (64 - strict)
+	maskL := ^uint64(0) << (64 - loose)
+
+	roll, judge := w.Roll, w.Judge
+	if judge == nil {
+		judge = func(fingerprint, mask uint64) bool {
+			return fingerprint&mask == 0
+		}
+	}
+
+	fp := uint64(0)
+	mask := maskS
+	for i := MinSize; i < n; i++ {
+		if i == NormalSize {
+			mask = maskL
+		}
+		fp = roll(fp, data[i])
+		if judge(fp, mask) {
+			return i
+		}
+	}
+	return n
+}
+
+// CheckCutpoints runs implementation over data with options as a chunker
+// would and reports the first cutpoint breaking the invariants every
+// algorithm must hold: chunks are at least MinSize bytes but for the last
+// one, at most MaxSize bytes, and cutpoints only depend on the data. It
+// is meant for the tests of new algorithms.
+func CheckCutpoints(implementation ChunkerImplementation, options *ChunkerOpts, data []byte) error {
+	if err := implementation.Validate(options); err != nil {
+		return err
+	}
+	for offset := 0; offset < len(data); {
+		n := min(len(data)-offset, options.MaxSize)
+		cutpoint := implementation.Algorithm(options, data[offset:], n)
+		switch {
+		case cutpoint <= 0 || cutpoint > n:
+			return fmt.Errorf("chunk at offset %d: cutpoint %d out of the %d bytes available", offset, cutpoint, n)
+		case cutpoint < options.MinSize && cutpoint != len(data)-offset:
+			return fmt.Errorf("chunk at offset %d: cutpoint %d below MinSize", offset, cutpoint)
+		}
+		if again := implementation.Algorithm(options, data[offset:], n); again != cutpoint {
+			return fmt.Errorf("chunk at offset %d: cutpoint %d then %d on the same data", offset, cutpoint, again)
+		}
+		offset += cutpoint
+	}
+	return nil
+}
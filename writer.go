@@ -0,0 +1,149 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+import (
+	"io"
+)
+
+// chunkWriter chunks the data written to it, for push-based pipelines
+// such as the receipt of uploads, which cannot hand a chunker a reader.
+// Writes are handed to a chunker reading from another goroutine, and
+// each only returns once the chunker waits for more data, so that emit is
+// only ever called during Write or Close.
+type chunkWriter struct {
+	data     chan []byte
+	consumed chan struct{}
+	done     chan error
+	err      error
+}
+
+// writerReader is the reader the chunker of a chunkWriter reads from.
+type writerReader struct {
+	data     chan []byte
+	consumed chan struct{}
+	pending  []byte
+	handed   bool
+}
+
+func (r *writerReader) Read(p []byte) (int, error) {
+	if len(r.pending) == 0 {
+		if r.handed {
+			r.handed = false
+			r.consumed <- struct{}{}
+		}
+		data, ok := <-r.data
+		if !ok {
+			return 0, io.EOF
+		}
+		r.pending, r.handed = data, true
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// NewWriter returns a writer chunking what is written to it with the
+// algorithm and options, as NewChunker would the same stream, and calling
+// emit with every chunk as it is cut, the tail being flushed by Close.
+// Writes may be of any size. A chunk is cut once enough data follows it,
+// at most MaxSize bytes, so emit is called from within Write or Close,
+// whose error is that of emit if it fails, unless MaxLatency lets time
+// cuts happen in between. Close must be called to release the goroutine
+// the chunker runs in.
+func NewWriter(algorithm string, opts *ChunkerOpts, emit func(Chunk) error) (io.WriteCloser, error) {
+	r := &writerReader{
+		data:     make(chan []byte),
+		consumed: make(chan struct{}),
+	}
+	chunker, err := NewChunker(algorithm, r, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &chunkWriter{
+		data:     r.data,
+		consumed: r.consumed,
+		done:     make(chan error, 1),
+	}
+	go func() {
+		for {
+			chunk, err := chunker.NextChunk()
+			if err != nil && err != io.EOF {
+				w.done <- err
+				return
+			}
+			if len(chunk.Data) != 0 {
+				if err := emit(chunk); err != nil {
+					w.done <- err
+					return
+				}
+			}
+			if err == io.EOF {
+				w.done <- nil
+				return
+			}
+		}
+	}()
+	return w, nil
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	select {
+	case w.data <- p:
+	case err := <-w.done:
+		return 0, w.fail(err)
+	}
+	select {
+	case <-w.consumed:
+	case err := <-w.done:
+		// p may have been partly chunked
+		return 0, w.fail(err)
+	}
+	return len(p), nil
+}
+
+// Close flushes the chunks left and returns the first error of emit or of
+// the chunker, if any.
+func (w *chunkWriter) Close() error {
+	if w.err == io.ErrClosedPipe {
+		return nil
+	}
+	if w.err != nil {
+		return w.err
+	}
+	close(w.data)
+	if err := <-w.done; err != nil {
+		return w.fail(err)
+	}
+	w.err = io.ErrClosedPipe
+	return nil
+}
+
+// fail records the error the chunker stopped with, returned by every call
+// from then on.
+func (w *chunkWriter) fail(err error) error {
+	w.err = err
+	return err
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2026 Gilles Chehade <gilles@poolp.org>
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package chunkers
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+const (
+	xxh64Prime1 uint64 = 0x9e3779b185ebca87
+	xxh64Prime2 uint64 = 0xc2b2ae3d27d4eb4f
+	xxh64Prime3 uint64 = 0x165667b19e3779f9
+	xxh64Prime4 uint64 = 0x85ebca77c2b2ae63
+	xxh64Prime5 uint64 = 0x27d4eb2f165667c5
+)
+
+type xxh64 struct {
+	v     [4]uint64
+	total uint64
+	buf   [32]byte
+	n     int
+}
+
+// NewXXH64 returns a zero-seeded XXH64 hash. It is not cryptographic and
+// only suits chunk IDs that never leave the local machine, such as cache
+// keys, where SHA-256 would be the bottleneck. hash/fnv offers a 128-bit
+// alternative with fnv.New128a.
+func NewXXH64() hash.Hash64 {
+	h := &xxh64{}
+	h.Reset()
+	return h
+}
+
+func (h *xxh64) Reset() {
+	prime1, prime2 := xxh64Prime1, xxh64Prime2
+	h.v = [4]uint64{prime1 + prime2, prime2, 0, -prime1}
+	h.total = 0
+	h.n = 0
+}
+
+func (h *xxh64) Size() int      { return 8 }
+func (h *xxh64) BlockSize() int { return 32 }
+
+func xxh64Round(acc, input uint64) uint64 {
+	acc += input * xxh64Prime2
+	acc = bits.RotateLeft64(acc, 31)
+	return acc * xxh64Prime1
+}
+
+func xxh64Merge(acc, value uint64) uint64 {
+	acc ^= xxh64Round(0, value)
+	return acc*xxh64Prime1 + xxh64Prime4
+}
+
+func (h *xxh64) stripe(b []byte) {
+	h.v[0] = xxh64Round(h.v[0], binary.LittleEndian.Uint64(b[0:8]))
+	h.v[1] = xxh64Round(h.v[1], binary.LittleEndian.Uint64(b[8:16]))
+	h.v[2] = xxh64Round(h.v[2], binary.LittleEndian.Uint64(b[16:24]))
+	h.v[3] = xxh64Round(h.v[3], binary.LittleEndian.Uint64(b[24:32]))
+}
+
+func (h *xxh64) Write(p []byte) (int, error) {
+	written := len(p)
+	h.total += uint64(written)
+
+	if h.n != 0 {
+		filled := copy(h.buf[h.n:], p)
+		h.n += filled
+		p = p[filled:]
+		if h.n < len(h.buf) {
+			return written, nil
+		}
+		h.stripe(h.buf[:])
+		h.n = 0
+	}
+	for ; len(p) >= 32; p = p[32:] {
+		h.stripe(p)
+	}
+	h.n = copy(h.buf[:], p)
+	return written, nil
+}
+
+func (h *xxh64) Sum64() uint64 {
+	var acc uint64
+	if h.total >= 32 {
+		acc = bits.RotateLeft64(h.v[0], 1) + bits.RotateLeft64(h.v[1], 7) +
+			bits.RotateLeft64(h.v[2], 12) + bits.RotateLeft64(h.v[3], 18)
+		for _, v := range h.v {
+			acc = xxh64Merge(acc, v)
+		}
+	} else {
+		acc = xxh64Prime5
+	}
+	acc += h.total
+
+	p := h.buf[:h.n]
+	for ; len(p) >= 8; p = p[8:] {
+		acc ^= xxh64Round(0, binary.LittleEndian.Uint64(p))
+		acc = bits.RotateLeft64(acc, 27)*xxh64Prime1 + xxh64Prime4
+	}
+	if len(p) >= 4 {
+		acc ^= uint64(binary.LittleEndian.Uint32(p)) * xxh64Prime1
+		acc = bits.RotateLeft64(acc, 23)*xxh64Prime2 + xxh64Prime3
+		p = p[4:]
+	}
+	for _, b := range p {
+		acc ^= uint64(b) * xxh64Prime5
+		acc = bits.RotateLeft64(acc, 11) * xxh64Prime1
+	}
+
+	acc ^= acc >> 33
+	acc *= xxh64Prime2
+	acc ^= acc >> 29
+	acc *= xxh64Prime3
+	acc ^= acc >> 32
+	return acc
+}
+
+// Sum appends the big-endian digest, as the reference implementation
+// prints it.
+func (h *xxh64) Sum(b []byte) []byte {
+	return binary.BigEndian.AppendUint64(b, h.Sum64())
+}